@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Nevoral/sqlofi/sqlite"
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+// Reading is a sensor measurement, the table the benchmark fills
+type Reading struct {
+	Id       int64   `sqlofi:"PRIMARY KEY AUTOINCREMENT"`
+	Sensor   string  `sqlofi:"NOT NULL"`
+	Value    float64 `sqlofi:"NOT NULL"`
+	Recorded int64   `sqlofi:"NOT NULL"`
+}
+
+// Compares BulkInsert with inserting the same rows one statement per row
+func main() {
+	rowCount := flag.Int("rows", 100000, "number of rows to insert")
+	batchSize := flag.Int("batch", 1000, "rows per BulkInsert transaction")
+	flag.Parse()
+
+	dir, err := os.MkdirTemp("", "sqlofi-bulk")
+	if err != nil {
+		log.Fatalf("Failed to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	rows := make([]Reading, *rowCount)
+	for i := range rows {
+		rows[i] = Reading{
+			Sensor:   fmt.Sprintf("sensor-%d", i%50),
+			Value:    float64(i) * 0.25,
+			Recorded: int64(i),
+		}
+	}
+
+	ctx := context.Background()
+
+	perRow := run(filepath.Join(dir, "per_row.db"), len(rows), func(db *sql.DB) error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		repo := sqlite.NewRepo[Reading](tx)
+		for i := range rows {
+			if err := repo.Insert(ctx, &rows[i]); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	fmt.Printf("Per-row inserts in one transaction: %v\n", perRow)
+
+	bulk := run(filepath.Join(dir, "bulk.db"), len(rows), func(db *sql.DB) error {
+		return sqlite.BulkInsert(ctx, db, rows, sqlite.BulkOptions{
+			BatchSize: *batchSize,
+			Progress: func(inserted, total int) {
+				fmt.Printf("\r  inserted %d of %d rows", inserted, total)
+			},
+		})
+	})
+	fmt.Printf("\nBulkInsert with batches of %d rows: %v\n", *batchSize, bulk)
+
+	if bulk > 0 {
+		fmt.Printf("BulkInsert is %.1fx faster\n", float64(perRow)/float64(bulk))
+	}
+}
+
+// run creates the table in a new database and returns how long inserting the want rows took
+func run(path string, want int, insert func(db *sql.DB) error) time.Duration {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqlite.CREATE_TABLE(Reading{}).Build()); err != nil {
+		log.Fatalf("Failed to create table: %v", err)
+	}
+
+	start := time.Now()
+	if err := insert(db); err != nil {
+		log.Fatalf("Failed to insert rows: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	var count int64
+	if err := db.QueryRow("SELECT count(*) FROM reading").Scan(&count); err != nil {
+		log.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != int64(want) {
+		log.Fatalf("Inserted %d rows instead of %d", count, want)
+	}
+	return elapsed
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -61,33 +62,41 @@ type OrderItem struct {
 func testExpressions() {
 	fmt.Println("===== Testing Expressions =====")
 
-	// Simple expressions
-	expr1 := sqlite.Expr("Price > 100")
-	fmt.Println("Expression 1:", expr1.Build())
+	price := sqlite.NewExpression("Price")
+	quantity := sqlite.NewExpression("Quantity")
 
-	expr2 := sqlite.Expr("Quantity BETWEEN 10 AND 100")
-	fmt.Println("Expression 2:", expr2.Build())
+	// Simple expressions, Go values are bound as parameters
+	expr1 := sqlite.GT(price, sqlite.Expr(100))
+	fmt.Println("Expression 1:", expr1.Inline())
+
+	expr2 := sqlite.BETWEEN(quantity, sqlite.Expr(10), sqlite.Expr(100))
+	fmt.Println("Expression 2:", expr2.Inline())
 
 	// Compound expressions
-	expr3 := sqlite.Expr("Price > 100 AND Quantity > 0")
-	fmt.Println("Expression 3:", expr3.Build())
+	expr3 := sqlite.AND(sqlite.GT(price, sqlite.Expr(100)), sqlite.GT(quantity, sqlite.Expr(0)))
+	fmt.Println("Expression 3:", expr3.Inline())
 
-	expr4 := sqlite.Expr("CategoryId IS NOT NULL OR Price < 10")
-	fmt.Println("Expression 4:", expr4.Build())
+	expr4 := sqlite.OR(sqlite.NOTNULL(sqlite.NewExpression("CategoryId")), sqlite.LT(price, sqlite.Expr(10)))
+	fmt.Println("Expression 4:", expr4.Inline())
 
 	// Function expressions
-	expr5 := sqlite.Expr("length(Name) > 5")
-	fmt.Println("Expression 5:", expr5.Build())
+	expr5 := sqlite.GT(sqlite.FUNC("length", sqlite.NewExpression("Name")), sqlite.Expr(5))
+	fmt.Println("Expression 5:", expr5.Inline())
 
-	expr6 := sqlite.Expr("datetime('now') > Created")
-	fmt.Println("Expression 6:", expr6.Build())
+	expr6 := sqlite.GT(sqlite.FUNC("datetime", sqlite.Expr("now")), sqlite.NewExpression("Created"))
+	fmt.Println("Expression 6:", expr6.Inline())
 
 	// Subquery expressions
-	expr7 := sqlite.Expr("Price > (SELECT AVG(Price) FROM product)")
-	fmt.Println("Expression 7:", expr7.Build())
+	averagePrice := sqlite.SELECT(sqlite.NOTHING, sqlite.NewExpressionColumn(sqlite.FUNC("AVG", price))).
+		FROM(sqlite.NewTableFrom("product"))
+	expr7 := sqlite.GT(price, sqlite.SELECTexpr(averagePrice))
+	fmt.Println("Expression 7:", expr7.Inline())
 
-	expr8 := sqlite.Expr("CategoryId IN (SELECT Id FROM Category WHERE Name LIKE 'Electronics%')")
-	fmt.Println("Expression 8:", expr8.Build())
+	electronics := sqlite.SELECT(sqlite.NOTHING, sqlite.NewExpressionColumn(sqlite.NewExpression("Id"))).
+		FROM(sqlite.NewTableFrom("category")).
+		WHERE(sqlite.LIKE(sqlite.NewExpression("Name"), sqlite.Expr("Electronics%"), nil))
+	expr8 := sqlite.IN_SELECT(sqlite.NewExpression("CategoryId"), electronics)
+	fmt.Println("Expression 8:", expr8.Inline())
 
 	fmt.Println()
 }
@@ -104,7 +113,7 @@ func testSelectStatements() {
 	).FROM(sqlite.NewTableFrom("product"))
 
 	fmt.Println("Simple SELECT:")
-	fmt.Println(select1.Build())
+	fmt.Println(select1.Inline())
 	fmt.Println()
 
 	// SELECT with WHERE clause
@@ -117,7 +126,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with WHERE:")
-	fmt.Println(select2.Build())
+	fmt.Println(select2.Inline())
 	fmt.Println()
 
 	// SELECT with column alias
@@ -130,7 +139,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with column aliases:")
-	fmt.Println(select3.Build())
+	fmt.Println(select3.Inline())
 	fmt.Println()
 
 	// SELECT with JOIN
@@ -147,7 +156,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with JOIN:")
-	fmt.Println(select4.Build())
+	fmt.Println(select4.Inline())
 	fmt.Println()
 
 	// SELECT with multiple JOINs
@@ -171,7 +180,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with multiple JOINs:")
-	fmt.Println(select5.Build())
+	fmt.Println(select5.Inline())
 	fmt.Println()
 
 	// SELECT with GROUP BY and HAVING
@@ -191,7 +200,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with GROUP BY and HAVING:")
-	fmt.Println(select6.Build())
+	fmt.Println(select6.Inline())
 	fmt.Println()
 
 	// SELECT with ORDER BY and LIMIT
@@ -205,7 +214,7 @@ func testSelectStatements() {
 	).Limit(10).Offset(20)
 
 	fmt.Println("SELECT with ORDER BY, LIMIT and OFFSET:")
-	fmt.Println(select7.Build())
+	fmt.Println(select7.Inline())
 	fmt.Println()
 
 	// SELECT with subquery
@@ -220,11 +229,11 @@ func testSelectStatements() {
 	).FROM(
 		sqlite.NewTableFrom("product"),
 	).WHERE(
-		sqlite.NewExpression(fmt.Sprintf("Price > (%s)", subquery.Build())),
+		sqlite.NewExpression(fmt.Sprintf("Price > (%s)", subquery.Inline())),
 	)
 
 	fmt.Println("SELECT with subquery in WHERE clause:")
-	fmt.Println(select8.Build())
+	fmt.Println(select8.Inline())
 	fmt.Println()
 
 	// SELECT with subquery in FROM
@@ -248,7 +257,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT with subquery in JOIN:")
-	fmt.Println(select9.Build())
+	fmt.Println(select9.Inline())
 	fmt.Println()
 
 	// SELECT DISTINCT
@@ -261,7 +270,7 @@ func testSelectStatements() {
 	)
 
 	fmt.Println("SELECT DISTINCT:")
-	fmt.Println(select10.Build())
+	fmt.Println(select10.Inline())
 	fmt.Println()
 }
 
@@ -431,25 +440,18 @@ func runSelectQueries(db *sql.DB) {
 	)
 
 	fmt.Println("\nQuery 1: Products with price > 100")
-	fmt.Println("SQL:", query1.Build())
+	query1SQL, _ := query1.Build()
+	fmt.Println("SQL:", query1SQL)
 
-	rows, err := db.Query(query1.Build())
+	products, err := sqlite.Query[Product](context.Background(), db, query1)
 	if err != nil {
 		log.Printf("Error executing query 1: %v", err)
 	} else {
-		defer rows.Close()
 		fmt.Println("Results:")
 		fmt.Println("ID | Name | Price")
 		fmt.Println("---------------")
-		var id int64
-		var name string
-		var price float64
-		for rows.Next() {
-			if err := rows.Scan(&id, &name, &price); err != nil {
-				log.Printf("Error scanning row: %v", err)
-				continue
-			}
-			fmt.Printf("%d | %s | %.2f\n", id, name, price)
+		for _, product := range products {
+			fmt.Printf("%d | %s | %.2f\n", product.Id, product.Name, product.Price)
 		}
 	}
 
@@ -467,24 +469,23 @@ func runSelectQueries(db *sql.DB) {
 	)
 
 	fmt.Println("\nQuery 2: Products with their categories")
-	fmt.Println("SQL:", query2.Build())
+	query2SQL, _ := query2.Build()
+	fmt.Println("SQL:", query2SQL)
 
-	rows, err = db.Query(query2.Build())
+	type productCategory struct {
+		ProductID    int64
+		ProductName  string
+		CategoryName sql.NullString
+	}
+	productCategories, err := sqlite.Query[productCategory](context.Background(), db, query2)
 	if err != nil {
 		log.Printf("Error executing query 2: %v", err)
 	} else {
-		defer rows.Close()
 		fmt.Println("Results:")
 		fmt.Println("ProductID | ProductName | CategoryName")
 		fmt.Println("------------------------------------")
-		var prodID int64
-		var prodName, catName string
-		for rows.Next() {
-			if err := rows.Scan(&prodID, &prodName, &catName); err != nil {
-				log.Printf("Error scanning row: %v", err)
-				continue
-			}
-			fmt.Printf("%d | %s | %s\n", prodID, prodName, catName)
+		for _, row := range productCategories {
+			fmt.Printf("%d | %s | %s\n", row.ProductID, row.ProductName, row.CategoryName.String)
 		}
 	}
 
@@ -513,9 +514,10 @@ func runSelectQueries(db *sql.DB) {
 	)
 
 	fmt.Println("\nQuery 3: Order details with customer and product info")
-	fmt.Println("SQL:", query3.Build())
+	query3SQL, query3Args := query3.Build()
+	fmt.Println("SQL:", query3SQL)
 
-	rows, err = db.Query(query3.Build())
+	rows, err := db.Query(query3SQL, query3Args...)
 	if err != nil {
 		log.Printf("Error executing query 3: %v", err)
 	} else {
@@ -552,9 +554,10 @@ func runSelectQueries(db *sql.DB) {
 	)
 
 	fmt.Println("\nQuery 4: Product count and average price by category")
-	fmt.Println("SQL:", query4.Build())
+	query4SQL, query4Args := query4.Build()
+	fmt.Println("SQL:", query4SQL)
 
-	rows, err = db.Query(query4.Build())
+	rows, err = db.Query(query4SQL, query4Args...)
 	if err != nil {
 		log.Printf("Error executing query 4: %v", err)
 	} else {
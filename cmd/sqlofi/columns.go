@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// columnsModel is a struct with sqlofi tagged fields found in the package sources
+type columnsModel struct {
+	name      string
+	fields    []string
+	tableName string // receiver expression calling TableName(), "" when the model has no such method
+}
+
+func runColumns(args []string) error {
+	var (
+		fs    = flag.NewFlagSet("columns", flag.ExitOnError)
+		dir   = fs.String("dir", ".", "directory of the models package")
+		out   = fs.String("out", "sqlofi_columns.go", "output file, relative to -dir")
+		names = fs.String("types", "", "comma separated models, all structs with sqlofi tags when empty")
+	)
+	fs.Parse(args)
+
+	outPath := *out
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(*dir, outPath)
+	}
+
+	pkgName, models, err := parseModels(*dir, outPath)
+	if err != nil {
+		return err
+	}
+	if *names != "" {
+		var selected []*columnsModel
+		for _, name := range strings.Split(*names, ",") {
+			name = strings.TrimSpace(name)
+			idx := slices.IndexFunc(models, func(m *columnsModel) bool { return m.name == name })
+			if idx == -1 {
+				return fmt.Errorf("model %s not found or has no sqlofi tagged fields", name)
+			}
+			selected = append(selected, models[idx])
+		}
+		models = selected
+	}
+	if len(models) == 0 {
+		return errors.New("no structs with sqlofi tagged fields found")
+	}
+
+	src, err := format.Source(generateColumns(pkgName, models))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, src, 0o644)
+}
+
+// parseModels reads the structs with sqlofi tagged fields of the Go files in dir, skipping the output file
+func parseModels(dir, outPath string) (string, []*columnsModel, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		fset       = token.NewFileSet()
+		pkgName    string
+		models     []*columnsModel
+		tableNames = map[string]string{}
+	)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || filepath.Clean(path) == filepath.Clean(outPath) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		if pkgName == "" {
+			pkgName = file.Name.Name
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					if model := structModel(spec.(*ast.TypeSpec)); model != nil {
+						models = append(models, model)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 {
+					continue
+				}
+				switch recv := decl.Recv.List[0].Type.(type) {
+				case *ast.Ident:
+					tableNames[recv.Name] = recv.Name + "{}"
+				case *ast.StarExpr:
+					if ident, ok := recv.X.(*ast.Ident); ok {
+						tableNames[ident.Name] = "(&" + ident.Name + "{})"
+					}
+				}
+			}
+		}
+	}
+
+	for _, model := range models {
+		model.tableName = tableNames[model.name]
+	}
+	slices.SortFunc(models, func(a, b *columnsModel) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return pkgName, models, nil
+}
+
+// structModel returns the model of a struct type with sqlofi tagged fields, nil for any other type
+func structModel(spec *ast.TypeSpec) *columnsModel {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || spec.TypeParams != nil {
+		return nil
+	}
+
+	model := &columnsModel{name: spec.Name.Name}
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		if value, ok := reflect.StructTag(tag).Lookup("sqlofi"); !ok || value == "-" {
+			continue
+		}
+		for _, name := range field.Names {
+			model.fields = append(model.fields, name.Name)
+		}
+	}
+	if len(model.fields) == 0 {
+		return nil
+	}
+	return model
+}
+
+func generateColumns(pkgName string, models []*columnsModel) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by sqlofi columns. DO NOT EDIT.\n\npackage %s\n\n", pkgName)
+	b.WriteString("import \"github.com/Nevoral/sqlofi/sqlite\"\n")
+
+	for _, model := range models {
+		table := strconv.Quote(utils.ToSnakeCase(model.name))
+		if model.tableName != "" {
+			table = model.tableName + ".TableName()"
+		}
+
+		fmt.Fprintf(&b, "\n// %sCols are the columns of the %s model\nvar %sCols = struct {\n", model.name, model.name, model.name)
+		for _, field := range model.fields {
+			fmt.Fprintf(&b, "\t%s sqlite.ColumnRef\n", field)
+		}
+		b.WriteString("}{\n")
+		for _, field := range model.fields {
+			fmt.Fprintf(&b, "\t%s: sqlite.NewColumnRef(%s, %q),\n", field, table, field)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes()
+}
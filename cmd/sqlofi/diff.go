@@ -0,0 +1,146 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// errSchemaDiffers makes diff exit with a non-zero status like diff(1)
+var errSchemaDiffers = errors.New("models and database differ")
+
+// openDatabase opens an existing database file, the file is never created
+func openDatabase(path string, readOnly bool) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	dsn := "file:" + path
+	if readOnly {
+		dsn += "?mode=ro"
+	}
+	return sql.Open("sqlite3", dsn)
+}
+
+// dbObject is an entry of sqlite_master
+type dbObject struct {
+	kind string
+	name string
+	sql  string
+}
+
+func (o dbObject) key() string {
+	return o.kind + " " + o.name
+}
+
+// schemaObjects lists the tables, indexes, views and triggers of the database,
+// without the internal sqlite_ objects and the migrations table
+func schemaObjects(db *sql.DB) ([]dbObject, error) {
+	rows, err := db.Query(`SELECT type, name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' AND name != ?
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 WHEN 'view' THEN 2 ELSE 3 END, name`, migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []dbObject
+	for rows.Next() {
+		var obj dbObject
+		if err := rows.Scan(&obj.kind, &obj.name, &obj.sql); err != nil {
+			return nil, err
+		}
+		objects = append(objects, obj)
+	}
+	return objects, rows.Err()
+}
+
+// normalizeSQL makes statements differing only in whitespace equal
+func normalizeSQL(statement string) string {
+	statement = strings.Join(strings.Fields(statement), " ")
+	statement = strings.ReplaceAll(statement, "( ", "(")
+	return strings.ReplaceAll(statement, " )", ")")
+}
+
+func runDiff(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("diff", flag.ExitOnError)
+		source schemaFlags
+		dbPath = fs.String("db", "", "database file to compare with")
+	)
+	source.register(fs)
+	fs.Parse(args)
+	if *dbPath == "" {
+		return errors.New("missing -db")
+	}
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+
+	// SQLite stores the CREATE statements as it received them, running the
+	// models DDL in memory gives statements comparable with the database file
+	memory, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return err
+	}
+	defer memory.Close()
+	if _, err := memory.Exec(schema.Build()); err != nil {
+		return fmt.Errorf("models DDL: %w", err)
+	}
+	want, err := schemaObjects(memory)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(*dbPath, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	have, err := schemaObjects(db)
+	if err != nil {
+		return err
+	}
+
+	haveByKey := map[string]dbObject{}
+	for _, obj := range have {
+		haveByKey[obj.key()] = obj
+	}
+	wantByKey := map[string]dbObject{}
+	for _, obj := range want {
+		wantByKey[obj.key()] = obj
+	}
+
+	differs := false
+	for _, obj := range want {
+		current, ok := haveByKey[obj.key()]
+		switch {
+		case !ok:
+			fmt.Printf("-- %s %s: missing in the database\n%s;\n\n", obj.kind, obj.name, obj.sql)
+		case normalizeSQL(current.sql) != normalizeSQL(obj.sql):
+			fmt.Printf("-- %s %s: differs\n-- database:\n--   %s\n-- models:\n%s;\n\n",
+				obj.kind, obj.name, strings.ReplaceAll(current.sql, "\n", "\n--   "), obj.sql)
+		default:
+			continue
+		}
+		differs = true
+	}
+	for _, obj := range have {
+		if _, ok := wantByKey[obj.key()]; !ok {
+			fmt.Printf("-- %s %s: not in the models\nDROP %s %s;\n\n", obj.kind, obj.name, strings.ToUpper(obj.kind), obj.name)
+			differs = true
+		}
+	}
+
+	if differs {
+		return errSchemaDiffers
+	}
+	fmt.Println("-- models and database match")
+	return nil
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+type introspectedColumn struct {
+	name      string
+	declType  string
+	notNull   bool
+	dflt      sql.NullString
+	pk        int
+	generated bool
+	unique    bool
+	reference *introspectedReference
+}
+
+type introspectedReference struct {
+	table    string
+	columns  []string
+	to       []string
+	onDelete string
+	onUpdate string
+}
+
+type introspectedIndex struct {
+	name    string
+	unique  bool
+	columns []string
+}
+
+type introspectedTable struct {
+	name          string
+	sql           string
+	columns       []*introspectedColumn
+	primaryKey    []string
+	uniques       [][]string
+	references    []*introspectedReference
+	indexes       []*introspectedIndex
+	autoincrement bool
+}
+
+func runIntrospect(args []string) error {
+	var (
+		fs      = flag.NewFlagSet("introspect", flag.ExitOnError)
+		dbPath  = fs.String("db", "", "database file to introspect")
+		pkgName = fs.String("package", "models", "package name of the generated file")
+		out     = fs.String("out", "", "output file, standard output when empty")
+	)
+	fs.Parse(args)
+	if *dbPath == "" {
+		return errors.New("missing -db")
+	}
+
+	db, err := openDatabase(*dbPath, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tables, err := introspectTables(db)
+	if err != nil {
+		return err
+	}
+
+	src, err := format.Source(generateModels(*pkgName, filepath.Base(*dbPath), tables))
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
+
+func introspectTables(db *sql.DB) ([]*introspectedTable, error) {
+	rows, err := db.Query(`SELECT name, sql FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != ? ORDER BY rowid`, migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	var tables []*introspectedTable
+	for rows.Next() {
+		t := &introspectedTable{}
+		if err := rows.Scan(&t.name, &t.sql); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.autoincrement = strings.Contains(strings.ToUpper(t.sql), "AUTOINCREMENT")
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		if err := t.introspect(db); err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.name, err)
+		}
+	}
+	return tables, nil
+}
+
+func (t *introspectedTable) column(name string) *introspectedColumn {
+	for _, col := range t.columns {
+		if col.name == name {
+			return col
+		}
+	}
+	return nil
+}
+
+func (t *introspectedTable) introspect(db *sql.DB) error {
+	rows, err := db.Query("SELECT cid, name, type, \"notnull\", dflt_value, pk, hidden FROM pragma_table_xinfo(?)", t.name)
+	if err != nil {
+		return err
+	}
+	pkColumns := map[int]string{}
+	for rows.Next() {
+		var (
+			cid, hidden int
+			col         = &introspectedColumn{}
+		)
+		if err := rows.Scan(&cid, &col.name, &col.declType, &col.notNull, &col.dflt, &col.pk, &hidden); err != nil {
+			rows.Close()
+			return err
+		}
+		col.generated = hidden == 2 || hidden == 3
+		if col.pk > 0 {
+			pkColumns[col.pk] = col.name
+		}
+		t.columns = append(t.columns, col)
+	}
+	rows.Close()
+	for i := 1; i <= len(pkColumns); i++ {
+		t.primaryKey = append(t.primaryKey, pkColumns[i])
+	}
+
+	// Foreign keys, rows of one constraint share the id
+	rows, err = db.Query("SELECT id, \"table\", \"from\", \"to\", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq", t.name)
+	if err != nil {
+		return err
+	}
+	byID := map[int]*introspectedReference{}
+	for rows.Next() {
+		var (
+			id   int
+			to   sql.NullString
+			ref  introspectedReference
+			from string
+		)
+		if err := rows.Scan(&id, &ref.table, &from, &to, &ref.onUpdate, &ref.onDelete); err != nil {
+			rows.Close()
+			return err
+		}
+		existing, ok := byID[id]
+		if !ok {
+			existing = &ref
+			byID[id] = existing
+			t.references = append(t.references, existing)
+		}
+		existing.columns = append(existing.columns, from)
+		if to.Valid {
+			existing.to = append(existing.to, to.String)
+		}
+	}
+	rows.Close()
+
+	// Single column foreign keys are written as the REFERENCES column constraint
+	var tableReferences []*introspectedReference
+	for _, ref := range t.references {
+		if col := t.column(ref.columns[0]); len(ref.columns) == 1 && col != nil && col.reference == nil {
+			col.reference = ref
+		} else {
+			tableReferences = append(tableReferences, ref)
+		}
+	}
+	t.references = tableReferences
+
+	rows, err = db.Query("SELECT name, \"unique\", origin FROM pragma_index_list(?) ORDER BY seq", t.name)
+	if err != nil {
+		return err
+	}
+	type indexEntry struct {
+		name   string
+		unique bool
+		origin string
+	}
+	var entries []indexEntry
+	for rows.Next() {
+		var entry indexEntry
+		if err := rows.Scan(&entry.name, &entry.unique, &entry.origin); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	rows.Close()
+
+	for _, entry := range entries {
+		columns, err := indexColumns(db, entry.name)
+		if err != nil {
+			return err
+		}
+		switch entry.origin {
+		case "u":
+			if col := t.column(columns[0]); len(columns) == 1 && col != nil {
+				col.unique = true
+			} else {
+				t.uniques = append(t.uniques, columns)
+			}
+		case "c":
+			if slices.Contains(columns, "") {
+				// Indexes on expressions can't be rebuilt from the pragmas
+				continue
+			}
+			t.indexes = append(t.indexes, &introspectedIndex{name: entry.name, unique: entry.unique, columns: columns})
+		}
+	}
+	return rows.Err()
+}
+
+func indexColumns(db *sql.DB, indexName string) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM pragma_index_info(?) ORDER BY seqno", indexName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name sql.NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns = append(columns, name.String)
+	}
+	return columns, rows.Err()
+}
+
+// goName converts a snake_case SQL name into an exported Go name
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == ' ' || r == '-' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if b.Len() == 0 || !isLetter(b.String()[0]) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+func isLetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// goType picks the Go type of a column from the affinity of its declared type
+func goType(col *introspectedColumn) string {
+	var (
+		declType = strings.ToUpper(col.declType)
+		nullable = !col.notNull && col.pk == 0
+	)
+	switch {
+	case strings.Contains(declType, "INT"):
+		if nullable {
+			return "sql.NullInt64"
+		}
+		return "int64"
+	case strings.Contains(declType, "CHAR"), strings.Contains(declType, "CLOB"), strings.Contains(declType, "TEXT"):
+		if nullable {
+			return "sql.NullString"
+		}
+		return "string"
+	case strings.Contains(declType, "BLOB"), declType == "":
+		return "[]byte"
+	default:
+		if nullable {
+			return "sql.NullFloat64"
+		}
+		return "float64"
+	}
+}
+
+// literalDefault matches the DEFAULT values that don't need parentheses
+var literalDefault = regexp.MustCompile(`(?i)^('([^']|'')*'|[+-]?(\d+(\.\d*)?|\.\d+)([eE][+-]?\d+)?|0x[0-9a-f]+|x'[0-9a-f]*'|NULL|TRUE|FALSE|CURRENT_TIME|CURRENT_DATE|CURRENT_TIMESTAMP)$`)
+
+func (t *introspectedTable) tag(col *introspectedColumn, structNames map[string]string) string {
+	var parts []string
+	if len(t.primaryKey) == 1 && col.pk == 1 {
+		parts = append(parts, "PRIMARY KEY")
+		if t.autoincrement && strings.EqualFold(col.declType, "INTEGER") {
+			parts = append(parts, "AUTOINCREMENT")
+		}
+	}
+	if col.notNull && !(len(t.primaryKey) == 1 && col.pk == 1) {
+		parts = append(parts, "NOT NULL")
+	}
+	if col.unique {
+		parts = append(parts, "UNIQUE")
+	}
+	if col.dflt.Valid {
+		if literalDefault.MatchString(col.dflt.String) {
+			parts = append(parts, "DEFAULT "+col.dflt.String)
+		} else {
+			parts = append(parts, "DEFAULT ("+col.dflt.String+")")
+		}
+	}
+	if ref := col.reference; ref != nil {
+		clause := "REFERENCES " + referencedStruct(ref.table, structNames)
+		if len(ref.to) > 0 {
+			clause += " (" + goName(ref.to[0]) + ")"
+		}
+		if ref.onDelete != "" && ref.onDelete != "NO ACTION" {
+			clause += " ON DELETE " + ref.onDelete
+		}
+		if ref.onUpdate != "" && ref.onUpdate != "NO ACTION" {
+			clause += " ON UPDATE " + ref.onUpdate
+		}
+		parts = append(parts, clause)
+	}
+	return strings.Join(parts, " ")
+}
+
+// referencedStruct names the model of a referenced table, tables outside the database keep their name
+func referencedStruct(table string, structNames map[string]string) string {
+	if name, ok := structNames[table]; ok {
+		return name
+	}
+	return table
+}
+
+func generateModels(pkgName, dbName string, tables []*introspectedTable) []byte {
+	var (
+		b           strings.Builder
+		structNames = map[string]string{}
+		models      []string
+		usesSQL     bool
+	)
+	for _, t := range tables {
+		structNames[t.name] = goName(t.name)
+		models = append(models, goName(t.name)+"{}")
+		for _, col := range t.columns {
+			usesSQL = usesSQL || strings.HasPrefix(goType(col), "sql.")
+		}
+	}
+
+	fmt.Fprintf(&b, "// Package %s holds the models of %s, generated by sqlofi introspect.\n", pkgName, dbName)
+	b.WriteString("// CHECK constraints and generated columns aren't introspected.\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", pkgName)
+	if usesSQL {
+		b.WriteString("\t\"database/sql\"\n\n")
+	}
+	b.WriteString("\t\"github.com/Nevoral/sqlofi/sqlite\"\n)\n\n")
+
+	fmt.Fprintf(&b, "// Schema returns the schema of %s\n", dbName)
+	fmt.Fprintf(&b, "func Schema() *sqlite.Schema {\n\treturn sqlite.NewSchema(%q).Model(%s)\n}\n", dbName, strings.Join(models, ", "))
+
+	for _, t := range tables {
+		name := structNames[t.name]
+		fmt.Fprintf(&b, "\n// %s is the %s table\ntype %s struct {\n", name, t.name, name)
+		for _, col := range t.columns {
+			if col.generated {
+				fmt.Fprintf(&b, "\t// %s is a generated column\n", col.name)
+				continue
+			}
+			field := goName(col.name)
+			if utils.ToSnakeCase(field) != col.name {
+				fmt.Fprintf(&b, "\t// the column %q doesn't map to the snake_case name of %s\n", col.name, field)
+			}
+			fmt.Fprintf(&b, "\t%s %s `sqlofi:%q`\n", field, goType(col), t.tag(col, structNames))
+		}
+		b.WriteString("}\n")
+
+		if utils.ToSnakeCase(name) != t.name {
+			fmt.Fprintf(&b, "\n// TableName returns the name of the %s table\nfunc (%s) TableName() string { return %q }\n", t.name, name, t.name)
+		}
+
+		if len(t.primaryKey) > 1 || len(t.uniques) > 0 || len(t.references) > 0 {
+			fmt.Fprintf(&b, "\n// TableOptions adds the table constraints of %s\nfunc (%s) TableOptions(t *sqlite.Table) {\n", t.name, name)
+			if len(t.primaryKey) > 1 {
+				fmt.Fprintf(&b, "\tt.PrimaryKey(\"\", sqlite.PRIMARY_KEY(%s))\n", indexedColumns(t.primaryKey))
+			}
+			for _, columns := range t.uniques {
+				fmt.Fprintf(&b, "\tt.Unique(\"\", sqlite.UNIQUE(%s))\n", indexedColumns(columns))
+			}
+			for _, ref := range t.references {
+				fmt.Fprintf(&b, "\tt.ForeignKey(\"\", sqlite.FOREIGN_KEY(&%s{}, %s)", referencedStruct(ref.table, structNames), quoted(ref.columns))
+				if len(ref.to) > 0 {
+					fmt.Fprintf(&b, ".ForeighColumns(%s)", quoted(ref.to))
+				}
+				if ref.onDelete != "" && ref.onDelete != "NO ACTION" {
+					fmt.Fprintf(&b, ".OnDelete(sqlite.%s)", strings.ReplaceAll(ref.onDelete, " ", "_"))
+				}
+				if ref.onUpdate != "" && ref.onUpdate != "NO ACTION" {
+					fmt.Fprintf(&b, ".OnUpdate(sqlite.%s)", strings.ReplaceAll(ref.onUpdate, " ", "_"))
+				}
+				b.WriteString(")\n")
+			}
+			b.WriteString("}\n")
+		}
+
+		if len(t.indexes) > 0 {
+			fmt.Fprintf(&b, "\n// Indexes returns the indexes of the %s table\nfunc (%s) Indexes() []*sqlite.Index {\n\treturn []*sqlite.Index{\n", t.name, name)
+			for _, idx := range t.indexes {
+				fmt.Fprintf(&b, "\t\tsqlite.CREATE_INDEX(%s{}, %q, %s)", name, idx.name, indexedColumns(idx.columns))
+				if idx.unique {
+					b.WriteString(".Unique()")
+				}
+				b.WriteString(",\n")
+			}
+			b.WriteString("\t}\n}\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func indexedColumns(columns []string) string {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = fmt.Sprintf("sqlite.NewIndexedColumn(%q)", col)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func quoted(values []string) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Nevoral/sqlofi/sqlite"
+)
+
+// finding is a single lint problem
+type finding struct {
+	object string
+	rule   string
+	msg    string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.object, f.msg, f.rule)
+}
+
+// lintRules are run in order on the schema file of the schema
+var lintRules = []func(def *sqlite.SchemaFile) []finding{
+	lintPrimaryKeys,
+	lintForeignKeys,
+	lintNames,
+	lintColumnTypes,
+}
+
+func runLint(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("lint", flag.ExitOnError)
+		source schemaFlags
+	)
+	source.register(fs)
+	fs.Parse(args)
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+
+	def := schema.File()
+	count := 0
+	for _, rule := range lintRules {
+		for _, f := range rule(def) {
+			fmt.Println(f)
+			count++
+		}
+	}
+	if count > 0 {
+		return fmt.Errorf("%d problems found", count)
+	}
+	return nil
+}
+
+// primaryKey returns the primary key columns of the table
+func primaryKey(table *sqlite.TableDef) []string {
+	var key []string
+	for _, col := range table.Columns {
+		for _, constraint := range col.Constraints {
+			if constraint.PrimaryKey != nil {
+				key = append(key, col.Name)
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.PrimaryKey != nil {
+			key = append(key, keyColumns(constraint.PrimaryKey.Columns)...)
+		}
+	}
+	return key
+}
+
+// uniqueKeys returns the column sets with a PRIMARY KEY, UNIQUE constraint or unique index
+func uniqueKeys(def *sqlite.SchemaFile, table *sqlite.TableDef) [][]string {
+	keys := [][]string{primaryKey(table)}
+	for _, col := range table.Columns {
+		for _, constraint := range col.Constraints {
+			if constraint.Unique != nil {
+				keys = append(keys, []string{col.Name})
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Unique != nil {
+			keys = append(keys, keyColumns(constraint.Unique.Columns))
+		}
+	}
+	for _, idx := range def.Indexes {
+		if idx.Table == table.Name && idx.Unique && idx.Where == "" {
+			keys = append(keys, keyColumns(idx.Columns))
+		}
+	}
+	return keys
+}
+
+func keyColumns(columns []sqlite.IndexedColumnDef) []string {
+	var names []string
+	for _, col := range columns {
+		names = append(names, col.Column)
+	}
+	return names
+}
+
+func findTable(def *sqlite.SchemaFile, name string) *sqlite.TableDef {
+	for i := range def.Tables {
+		if def.Tables[i].Name == name {
+			return &def.Tables[i]
+		}
+	}
+	return nil
+}
+
+func lintPrimaryKeys(def *sqlite.SchemaFile) []finding {
+	var findings []finding
+	for _, table := range def.Tables {
+		if table.As == "" && len(primaryKey(&table)) == 0 {
+			findings = append(findings, finding{table.Name, "primary-key", "table has no PRIMARY KEY"})
+		}
+	}
+	return findings
+}
+
+// foreignKey is a foreign key of the schema file whether declared on a column or on the table
+type foreignKey struct {
+	table   string
+	columns []string
+	ref     sqlite.ReferencesDef
+}
+
+func foreignKeys(table *sqlite.TableDef) []foreignKey {
+	var keys []foreignKey
+	for _, col := range table.Columns {
+		for _, constraint := range col.Constraints {
+			if constraint.References != nil {
+				keys = append(keys, foreignKey{table.Name, []string{col.Name}, *constraint.References})
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.ForeignKey != nil {
+			keys = append(keys, foreignKey{table.Name, constraint.ForeignKey.Columns, constraint.ForeignKey.References})
+		}
+	}
+	return keys
+}
+
+func lintForeignKeys(def *sqlite.SchemaFile) []finding {
+	var findings []finding
+	for _, table := range def.Tables {
+		for _, key := range foreignKeys(&table) {
+			object := fmt.Sprintf("%s(%s)", key.table, strings.Join(key.columns, ", "))
+
+			// SQLite reports "foreign key mismatch" only when the table is written to
+			parent := findTable(def, key.ref.Table)
+			switch {
+			case parent == nil:
+				findings = append(findings, finding{object, "foreign-key-target",
+					fmt.Sprintf("references table %s which isn't in the schema", key.ref.Table)})
+			case len(key.ref.Columns) == 0 && len(primaryKey(parent)) == 0:
+				findings = append(findings, finding{object, "foreign-key-target",
+					fmt.Sprintf("references the primary key of %s which has none", parent.Name)})
+			case len(key.ref.Columns) > 0 && !slices.ContainsFunc(uniqueKeys(def, parent), func(unique []string) bool {
+				return sameSet(unique, key.ref.Columns)
+			}):
+				findings = append(findings, finding{object, "foreign-key-target",
+					fmt.Sprintf("references %s(%s) which isn't a PRIMARY KEY or UNIQUE", parent.Name, strings.Join(key.ref.Columns, ", "))})
+			}
+
+			if !indexed(def, &table, key.columns) {
+				findings = append(findings, finding{object, "foreign-key-index",
+					fmt.Sprintf("foreign key has no index, every change of %s scans %s", key.ref.Table, table.Name)})
+			}
+		}
+	}
+	return findings
+}
+
+// indexed reports whether an index, key or unique constraint starts with the columns
+func indexed(def *sqlite.SchemaFile, table *sqlite.TableDef, columns []string) bool {
+	prefix := func(key []string) bool {
+		return len(key) >= len(columns) && sameSet(key[:len(columns)], columns)
+	}
+	if slices.ContainsFunc(uniqueKeys(def, table), prefix) {
+		return true
+	}
+	for _, idx := range def.Indexes {
+		if idx.Table == table.Name && idx.Where == "" && prefix(keyColumns(idx.Columns)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, name := range a {
+		if !slices.Contains(b, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func lintNames(def *sqlite.SchemaFile) []finding {
+	var (
+		findings []finding
+		seen     = map[string]string{}
+	)
+	declare := func(kind, name string) {
+		if reservedWord(name) {
+			findings = append(findings, finding{name, "reserved-name",
+				fmt.Sprintf("%s name is an SQLite keyword, the generated SQL doesn't quote it", kind)})
+		}
+		if previous, ok := seen[strings.ToLower(name)]; ok {
+			findings = append(findings, finding{name, "duplicate-name",
+				fmt.Sprintf("%s has the name of a %s, SQLite keeps them in one namespace", kind, previous)})
+		}
+		seen[strings.ToLower(name)] = kind
+	}
+
+	for _, table := range def.Tables {
+		declare("table", table.Name)
+		for _, col := range table.Columns {
+			if reservedWord(col.Name) {
+				findings = append(findings, finding{table.Name + "." + col.Name, "reserved-name",
+					"column name is an SQLite keyword, the generated SQL doesn't quote it"})
+			}
+		}
+	}
+	for _, idx := range def.Indexes {
+		declare("index", idx.Name)
+	}
+	for _, view := range def.Views {
+		declare("view", view.Name)
+	}
+	for _, trigger := range def.Triggers {
+		declare("trigger", trigger.Name)
+	}
+	return findings
+}
+
+func lintColumnTypes(def *sqlite.SchemaFile) []finding {
+	var findings []finding
+	for _, table := range def.Tables {
+		for _, col := range table.Columns {
+			if col.Type == "" || strings.EqualFold(col.Type, "NULL") {
+				findings = append(findings, finding{table.Name + "." + col.Name, "column-type",
+					"column has no type, it takes the values of any type"})
+			}
+		}
+	}
+	return findings
+}
+
+func reservedWord(name string) bool {
+	_, ok := sqliteKeywords[strings.ToUpper(name)]
+	return ok
+}
+
+// sqliteKeywords are the keywords of https://www.sqlite.org/lang_keywords.html
+var sqliteKeywords = func() map[string]struct{} {
+	keywords := map[string]struct{}{}
+	for _, word := range strings.Fields(`ABORT ACTION ADD AFTER ALL ALTER ALWAYS ANALYZE AND AS ASC ATTACH
+		AUTOINCREMENT BEFORE BEGIN BETWEEN BY CASCADE CASE CAST CHECK COLLATE COLUMN COMMIT CONFLICT
+		CONSTRAINT CREATE CROSS CURRENT CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP DATABASE DEFAULT
+		DEFERRABLE DEFERRED DELETE DESC DETACH DISTINCT DO DROP EACH ELSE END ESCAPE EXCEPT EXCLUDE
+		EXCLUSIVE EXISTS EXPLAIN FAIL FILTER FIRST FOLLOWING FOR FOREIGN FROM FULL GENERATED GLOB GROUP
+		GROUPS HAVING IF IGNORE IMMEDIATE IN INDEX INDEXED INITIALLY INNER INSERT INSTEAD INTERSECT INTO
+		IS ISNULL JOIN KEY LAST LEFT LIKE LIMIT MATCH MATERIALIZED NATURAL NO NOT NOTHING NOTNULL NULL
+		NULLS OF OFFSET ON OR ORDER OTHERS OUTER OVER PARTITION PLAN PRAGMA PRECEDING PRIMARY QUERY
+		RAISE RANGE RECURSIVE REFERENCES REGEXP REINDEX RELEASE RENAME REPLACE RESTRICT RETURNING RIGHT
+		ROLLBACK ROW ROWS SAVEPOINT SELECT SET TABLE TEMP TEMPORARY THEN TIES TO TRANSACTION TRIGGER
+		UNBOUNDED UNION UNIQUE UPDATE USING VACUUM VALUES VIEW VIRTUAL WHEN WHERE WINDOW WITH WITHOUT`) {
+		keywords[word] = struct{}{}
+	}
+	return keywords
+}()
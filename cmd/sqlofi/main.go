@@ -0,0 +1,82 @@
+// Command sqlofi builds, compares, migrates, introspects, lints and draws SQLite schemas.
+//
+// Usage:
+//
+//	sqlofi build      -pkg ./models              print the DDL of the models
+//	sqlofi diff       -pkg ./models -db app.db   compare the models with a database
+//	sqlofi migrate    up|down|status -db app.db  apply, revert or list SQL migrations
+//	sqlofi introspect -db app.db                 write Go structs for the database tables
+//	sqlofi lint       -pkg ./models              report schema problems
+//	sqlofi erd        -pkg ./models              draw the entity-relationship diagram
+//	sqlofi columns    -dir ./models              write typed column descriptors of the models
+//
+// The models package exposes its schema through a function, Schema by default:
+//
+//	func Schema() *sqlite.Schema
+//
+// sqlofi generates a small program importing the package, runs it with the go command
+// and reads the schema back as JSON. Every command accepting -pkg accepts a schema
+// file written by Schema.ExportFile through -schema instead.
+//
+// The columns command is meant for go generate, next to the models:
+//
+//	//go:generate go run github.com/Nevoral/sqlofi/cmd/sqlofi columns
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: sqlofi <command> [flags]
+
+commands:
+  build       print the DDL of a models package or schema file
+  diff        compare the models with a database file
+  migrate     apply, revert or list SQL migrations (up, down, status)
+  introspect  write Go structs for the tables of a database file
+  lint        report problems of the schema
+  erd         draw the entity-relationship diagram
+  columns     write typed column descriptors of the models, for go generate
+
+Run "sqlofi <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var (
+		args = os.Args[2:]
+		err  error
+	)
+	switch os.Args[1] {
+	case "build":
+		err = runBuild(args)
+	case "diff":
+		err = runDiff(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "introspect":
+		err = runIntrospect(args)
+	case "lint":
+		err = runLint(args)
+	case "erd":
+		err = runERD(args)
+	case "columns":
+		err = runColumns(args)
+	case "help", "-h", "-help", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "sqlofi: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sqlofi %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// migrationsTable records the applied migrations
+const migrationsTable = "sqlofi_migrations"
+
+// migrationFile matches "<version>_<name>.up.sql" and "<version>_<name>.down.sql"
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+type migration struct {
+	version int64
+	prefix  string // version as written in the file name, e.g. "0001"
+	name    string
+	up      string
+	down    string
+}
+
+func (m *migration) String() string {
+	return fmt.Sprintf("%s_%s", m.prefix, m.name)
+}
+
+// readMigrations reads the migration files of the directory ordered by version
+func readMigrations(dir string) ([]*migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*migration{}
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, prefix: match[1], name: match[2]}
+			byVersion[version] = m
+		} else if m.name != match[2] {
+			return nil, fmt.Errorf("migrations %s and %s_%s share the version", m, match[1], match[2])
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if match[3] == "up" {
+			m.up = path
+		} else {
+			m.down = path
+		}
+	}
+
+	var migrations []*migration
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %s has no .up.sql file", m)
+		}
+		migrations = append(migrations, m)
+	}
+	slices.SortFunc(migrations, func(a, b *migration) int {
+		return cmp.Compare(a.version, b.version)
+	})
+	return migrations, nil
+}
+
+// createMigrationsTable creates the table recording the applied migrations unless it exists
+func createMigrationsTable(db *sql.DB) error {
+	_, err := db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	applied_at TEXT NOT NULL
+)`, migrationsTable))
+	return err
+}
+
+// appliedMigrations returns the applied versions with the time they were applied,
+// without the migrations table no migration was applied yet
+func appliedMigrations(db *sql.DB) (map[int64]string, error) {
+	var tables int
+	err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = ?", migrationsTable).Scan(&tables)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[int64]string{}
+	if tables == 0 {
+		return applied, nil
+	}
+
+	rows, err := db.Query(fmt.Sprintf("SELECT version, applied_at FROM %s", migrationsTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			version   int64
+			appliedAt string
+		)
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// runMigration executes the file and records the change in one transaction
+func runMigration(db *sql.DB, path, record string, args ...any) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(string(content)); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing subcommand: up, down or status")
+	}
+
+	var (
+		action = args[0]
+		fs     = flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+		dbPath = fs.String("db", "", "database file, up creates it when missing")
+		dir    = fs.String("dir", "migrations", "directory of the <version>_<name>.up.sql and .down.sql files")
+		steps  = fs.Int("n", 0, "number of migrations to apply or revert, up applies all and down reverts one by default")
+	)
+	fs.Parse(args[1:])
+	if *dbPath == "" {
+		return errors.New("missing -db")
+	}
+
+	migrations, err := readMigrations(*dir)
+	if err != nil {
+		return err
+	}
+
+	// Only up writes the migrations table, status just reads the database
+	var db *sql.DB
+	switch action {
+	case "up":
+		db, err = sql.Open("sqlite3", "file:"+*dbPath)
+	case "status":
+		db, err = openDatabase(*dbPath, true)
+	default:
+		db, err = openDatabase(*dbPath, false)
+	}
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if action == "up" {
+		if err := createMigrationsTable(db); err != nil {
+			return err
+		}
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		count := 0
+		for _, m := range migrations {
+			if _, ok := applied[m.version]; ok {
+				continue
+			}
+			if *steps > 0 && count == *steps {
+				break
+			}
+			err := runMigration(db, m.up,
+				fmt.Sprintf("INSERT INTO %s (version, name, applied_at) VALUES (?, ?, ?)", migrationsTable),
+				m.version, m.name, time.Now().UTC().Format(time.RFC3339))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("applied  %s\n", m)
+			count++
+		}
+		if count == 0 {
+			fmt.Println("no pending migrations")
+		}
+
+	case "down":
+		if *steps == 0 {
+			*steps = 1
+		}
+		count := 0
+		for _, m := range slices.Backward(migrations) {
+			if count == *steps {
+				break
+			}
+			if _, ok := applied[m.version]; !ok {
+				continue
+			}
+			if m.down == "" {
+				return fmt.Errorf("migration %s has no .down.sql file", m)
+			}
+			err := runMigration(db, m.down,
+				fmt.Sprintf("DELETE FROM %s WHERE version = ?", migrationsTable), m.version)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("reverted %s\n", m)
+			count++
+		}
+		if count == 0 {
+			fmt.Println("no applied migrations")
+		}
+
+	case "status":
+		for _, m := range migrations {
+			if appliedAt, ok := applied[m.version]; ok {
+				fmt.Printf("applied  %s  %s\n", appliedAt, m)
+			} else {
+				fmt.Printf("pending  %-20s  %s\n", "", m)
+			}
+			delete(applied, m.version)
+		}
+		for _, version := range slices.Sorted(maps.Keys(applied)) {
+			fmt.Printf("missing  %d: applied but its files are gone\n", version)
+		}
+
+	default:
+		return fmt.Errorf("unknown subcommand %q, use up, down or status", action)
+	}
+	return nil
+}
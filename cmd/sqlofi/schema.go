@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/Nevoral/sqlofi/sqlite"
+)
+
+// schemaFlags selects where the schema of a command comes from
+type schemaFlags struct {
+	pkg    string
+	fn     string
+	schema string
+}
+
+func (f *schemaFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.pkg, "pkg", "", "directory of the models package")
+	fs.StringVar(&f.fn, "func", "Schema", "function of the models package returning its *sqlite.Schema")
+	fs.StringVar(&f.schema, "schema", "", "schema file (.json, .yaml or .yml) used instead of -pkg")
+}
+
+// load returns the schema of the models package or of the schema file
+func (f *schemaFlags) load() (*sqlite.Schema, error) {
+	switch {
+	case f.schema != "" && f.pkg != "":
+		return nil, errors.New("use either -pkg or -schema")
+	case f.schema != "":
+		return sqlite.LoadSchemaFile(f.schema)
+	case f.pkg != "":
+		return loadPackage(f.pkg, f.fn)
+	default:
+		return nil, errors.New("missing -pkg or -schema")
+	}
+}
+
+var loaderTemplate = template.Must(template.New("loader").Parse(`// Code generated by sqlofi. DO NOT EDIT.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Nevoral/sqlofi/sqlite"
+	models {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	data, err := models.{{.Func}}().Export(sqlite.JSON_FORMAT)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(data)
+}
+`))
+
+// loadPackage generates a program printing the schema of the package as JSON,
+// runs it inside the module of the package and loads the printed schema
+func loadPackage(dir, fn string) (*sqlite.Schema, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	list := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	list.Dir = dir
+	list.Stderr = os.Stderr
+	out, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list %s: %w", dir, err)
+	}
+	importPath := strings.TrimSpace(string(out))
+
+	tmp, err := os.MkdirTemp("", "sqlofi-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	var src bytes.Buffer
+	if err := loaderTemplate.Execute(&src, map[string]string{"ImportPath": importPath, "Func": fn}); err != nil {
+		return nil, err
+	}
+	mainFile := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(mainFile, src.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	// Running a file from the package directory resolves the imports with the module of the package
+	run := exec.Command("go", "run", mainFile)
+	run.Dir = dir
+	run.Stderr = os.Stderr
+	data, err := run.Output()
+	if err != nil {
+		return nil, fmt.Errorf("loading schema of %s: %w", importPath, err)
+	}
+	return sqlite.LoadSchema(data, sqlite.JSON_FORMAT)
+}
+
+func runBuild(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("build", flag.ExitOnError)
+		source schemaFlags
+	)
+	source.register(fs)
+	fs.Parse(args)
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+	fmt.Print(schema.Build())
+	return nil
+}
+
+func runERD(args []string) error {
+	var (
+		fs     = flag.NewFlagSet("erd", flag.ExitOnError)
+		source schemaFlags
+		format = fs.String("format", "mermaid", "diagram format: mermaid or dot")
+	)
+	source.register(fs)
+	fs.Parse(args)
+
+	var diagram sqlite.DiagramFormat
+	switch *format {
+	case "mermaid":
+		diagram = sqlite.MERMAID
+	case "dot", "graphviz":
+		diagram = sqlite.GRAPHVIZ
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	schema, err := source.load()
+	if err != nil {
+		return err
+	}
+	fmt.Print(schema.ERDiagram(diagram))
+	return nil
+}
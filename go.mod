@@ -2,7 +2,10 @@ module github.com/Nevoral/sqlofi
 
 go 1.23.4
 
-require github.com/mattn/go-sqlite3 v1.14.24
+require (
+	github.com/mattn/go-sqlite3 v1.14.24
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/antlr4-go/antlr/v4 v4.13.0 // indirect
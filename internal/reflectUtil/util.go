@@ -1,6 +1,39 @@
 package reflectutil
 
-import "reflect"
+import (
+	"reflect"
+
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// Implements reports whether the model, or a pointer to it, implements T.
+// Models are usually passed by value while their methods may have pointer receivers.
+func Implements[T any](model any) (T, bool) {
+	if impl, ok := model.(T); ok {
+		return impl, true
+	}
+
+	value := reflect.ValueOf(model)
+	if value.IsValid() && value.Kind() != reflect.Ptr {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		if impl, ok := ptr.Interface().(T); ok {
+			return impl, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// GetTableName returns the SQL table name of a model, either from its
+// TableName() method or as the snake_case name of the struct
+func GetTableName(table any) string {
+	if namer, ok := Implements[interface{ TableName() string }](table); ok {
+		return namer.TableName()
+	}
+	return utils.ToSnakeCase(GetStructName(table))
+}
 
 // GetStructName returns the name of the struct referenced in the foreign key
 func GetStructName(table any) string {
@@ -20,8 +53,13 @@ func GetStructName(table any) string {
 	return tableName
 }
 
-// GetStructFieldsNames returns the names of all parameters/fields in the referenced struct
+// GetStructFieldsNames returns the names of all parameters/fields in the referenced struct,
+// tables defined without a struct report their column names instead
 func GetStructFieldsNames(table any) []string {
+	if columns, ok := table.(interface{ ColumnNames() []string }); ok {
+		return columns.ColumnNames()
+	}
+
 	var fields []string
 	tableValue := reflect.ValueOf(table)
 
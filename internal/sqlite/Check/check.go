@@ -15,5 +15,5 @@ type Check struct {
 }
 
 func (c *Check) Build() string {
-	return fmt.Sprintf("CHECK (%s)", c.Expression.Build())
+	return fmt.Sprintf("CHECK (%s)", c.Expression.Inline())
 }
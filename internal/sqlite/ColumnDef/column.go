@@ -3,11 +3,15 @@ package column
 import (
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
 
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+
 	check "github.com/Nevoral/sqlofi/internal/sqlite/Check"
 	collate "github.com/Nevoral/sqlofi/internal/sqlite/Collate"
 	defaultConstr "github.com/Nevoral/sqlofi/internal/sqlite/Default"
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
 	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
 	foreignkey "github.com/Nevoral/sqlofi/internal/sqlite/ForeignKey"
 	generated "github.com/Nevoral/sqlofi/internal/sqlite/Generated"
@@ -19,19 +23,6 @@ import (
 	"github.com/Nevoral/sqlofi/internal/utils"
 )
 
-func isConstraintKeyword(token string) bool {
-	token = strings.ToUpper(token)
-	return strings.Contains(string(CONSTRAINT), token) ||
-		strings.Contains(string(PRIMARY_KEY), token) ||
-		strings.Contains(string(NOT_NULL), token) ||
-		strings.Contains(string(UNIQUE), token) ||
-		strings.Contains(string(CHECK), token) ||
-		strings.Contains(string(DEFAULT), token) ||
-		strings.Contains(string(COLLATE), token) ||
-		strings.Contains(string(REFERENCES), token) ||
-		strings.Contains(string(GENERATED), token)
-}
-
 type constraintToken string
 
 const (
@@ -45,13 +36,15 @@ const (
 	REFERENCES  constraintToken = "REFERENCES"
 	GENERATED   constraintToken = "GENERATED"
 	AS          constraintToken = "AS"
+	INDEX       constraintToken = "INDEX"
 )
 
-// ParseStructField parses a Go struct field into a Column definition
-func ParseStructField(models []any, field reflect.StructField) *Column {
-	tag := field.Tag.Get("sqlofi")
-	if tag == "" || tag == "-" {
-		return nil
+// ParseStructField parses a Go struct field into a Column definition.
+// Fields without a sqlofi tag or tagged with "-" are not columns and return nil.
+func ParseStructField(models []any, field reflect.StructField) (*Column, error) {
+	tag, ok := field.Tag.Lookup("sqlofi")
+	if !ok || tag == "-" {
+		return nil, nil
 	}
 
 	colName := utils.ToSnakeCase(field.Name)
@@ -64,11 +57,11 @@ func ParseStructField(models []any, field reflect.StructField) *Column {
 	column.models = models
 
 	// Parse tag options
-	if tag != "" && tag != "-" {
-		column.parseColumnTag(tag)
+	if err := column.parseColumnTag(tag); err != nil {
+		return nil, err
 	}
 
-	return column
+	return column, nil
 }
 
 func NewColumn(name string, colType types.SQLiteType) *Column {
@@ -79,10 +72,11 @@ func NewColumn(name string, colType types.SQLiteType) *Column {
 }
 
 type Column struct {
-	name       string
-	colType    types.SQLiteType
-	constraint []string
-	models     []any
+	name        string
+	colType     types.SQLiteType
+	constraints []*Constraint
+	models      []any
+	indexes     []*ColumnIndex
 
 	// Track which constraints have been added to prevent duplicates
 	// and enforce constraint compatibility
@@ -98,148 +92,121 @@ type Column struct {
 }
 
 // parseColumnTag parses the "sqlofi" tag and applies constraints to the column
-func (c *Column) parseColumnTag(tag string) {
-	if tag == "" {
-		return
+func (c *Column) parseColumnTag(tag string) error {
+	defs, err := parseFieldTag(c.name, tag)
+	if err != nil {
+		return err
 	}
 
-	tokens := strings.Fields(tag)
-	if len(tokens) == 0 {
-		return
+	for _, def := range defs {
+		if err := c.apply(def); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// Process the rest of the tokens
-	for i := 0; i < len(tokens); i++ {
-		var (
-			token          = strings.ToUpper(tokens[i])
-			constraintName string
-		)
-
-		// Handle named constraints with "CONSTRAINT" keyword
-		if token == string(CONSTRAINT) && i+2 < len(tokens) {
-			constraintName = tokens[i+1]
-			token = strings.ToUpper(tokens[i+2])
-			i += 2 // Move past constraint name and to the constraint type
+// apply adds a parsed constraint definition to the column
+func (c *Column) apply(def *constraintDef) error {
+	switch def.kind {
+	case PRIMARY_KEY:
+		c.PrimaryKey(def.name, def.sortOrder, def.conflict, def.autoincrement)
+	case NOT_NULL:
+		c.NotNull(def.name, def.conflict)
+	case UNIQUE:
+		c.Unique(def.name, def.conflict)
+	case CHECK:
+		c.Check(def.name, expr.NewExpression(def.expression))
+	case DEFAULT:
+		c.Default(def.name, def.expression)
+	case COLLATE:
+		c.Collate(def.name, def.collation)
+	case REFERENCES:
+		ref, err := c.resolveReference(def)
+		if err != nil {
+			return err
 		}
+		c.References(def.name, ref)
+	case GENERATED:
+		c.Generated(def.name, def.always, expr.NewExpression(def.expression), def.storage)
+	case INDEX:
+		c.Index(def.name, def.unique, def.sortOrder)
+	}
+	return nil
+}
 
-		switch {
-		case strings.Contains(string(PRIMARY_KEY), token):
-			i++
-			// Similar logic as above but without constraint name
-			sortOrder := sortorder.ASC
-			conflict := ""
-			autoincrement := false
-
-			for j := i + 1; j < len(tokens); j++ {
-				opt := strings.ToUpper(tokens[j])
-				if opt == "ASC" {
-					sortOrder = sortorder.ASC
-					i = j
-				} else if opt == "DESC" {
-					sortOrder = sortorder.DESC
-					i = j
-				} else if opt == "AUTOINCREMENT" {
-					autoincrement = true
-					i = j
-				} else if strings.HasPrefix(opt, "ON") && j+1 < len(tokens) {
-					conflictStr := strings.ToUpper(tokens[j+1])
-					conflict = parseConflictClause(conflictStr)
-					i = j + 1
-				} else if isConstraintKeyword(opt) {
-					i = j - 1
-					break
-				}
-			}
-
-			c.PrimaryKey(constraintName, sortOrder, conflict, autoincrement)
-
-		case strings.Contains(string(NOT_NULL), token):
-			i++
-			conflict := ""
-			if i+2 < len(tokens) && strings.ToUpper(tokens[i+1]) == "ON" &&
-				strings.ToUpper(tokens[i+2]) == "CONFLICT" && i+3 < len(tokens) {
-				conflictStr := strings.ToUpper(tokens[i+3])
-				conflict = parseConflictClause(conflictStr)
-				i += 3
-			}
-
-			c.NotNull(constraintName, conflict)
-
-		case strings.Contains(string(UNIQUE), token):
-			conflict := ""
-			if i+2 < len(tokens) && strings.ToUpper(tokens[i+1]) == "ON" &&
-				strings.ToUpper(tokens[i+2]) == "CONFLICT" && i+3 < len(tokens) {
-				conflictStr := strings.ToUpper(tokens[i+3])
-				conflict = parseConflictClause(conflictStr)
-				i += 3
-			}
-
-			c.Unique(constraintName, conflict)
-
-		case strings.Contains(string(CHECK), token):
-			checkExpr := findBalancedParentheses(tokens, i+1)
-			if checkExpr != "" {
-				expr := expr.NewExpression(checkExpr)
-				c.Check(constraintName, expr)
-				i += strings.Count(checkExpr, " ") + 1
-			}
-
-		case strings.Contains(string(DEFAULT), token):
-			defaultVal := ""
-			if i+1 < len(tokens) {
-				if tokens[i+1] == "(" {
-					defaultVal = findBalancedParentheses(tokens, i+1)
-					i += strings.Count(defaultVal, " ") + 1
-				} else {
-					defaultVal = tokens[i+1]
-					i++
-				}
-
-				c.Default(constraintName, defaultVal)
-			}
-
-		case strings.Contains(string(COLLATE), token):
-			if i+1 < len(tokens) {
-				c.Collate(constraintName, tokens[i+1])
-				i++
-			}
-
-		case strings.Contains(string(REFERENCES), token):
-			refStr := extractReferencesClause(tokens[i:])
-			if refStr != "" {
-				c.ForeignKey(constraintName, refStr)
-				i += strings.Count(refStr, " ")
-			}
-
-		case strings.Contains(string(GENERATED), token):
-			if i+3 < len(tokens) && strings.ToUpper(tokens[i+1]) == "ALWAYS" &&
-				strings.ToUpper(tokens[i+2]) == "AS" {
-
-				exprStr := findBalancedParentheses(tokens, i+3)
-				if exprStr != "" {
-					generatedExpr := expr.NewExpression(exprStr)
-
-					storageType := generated.VIRTUAL // Default
-					nextPos := i + 3 + strings.Count(exprStr, " ") + 1
-
-					if nextPos < len(tokens) {
-						storageStr := strings.ToUpper(tokens[nextPos])
-						if storageStr == "STORED" {
-							storageType = generated.STORED
-							i = nextPos
-						} else if storageStr == "VIRTUAL" {
-							storageType = generated.VIRTUAL
-							i = nextPos
-						}
-					}
-
-					c.Generated(constraintName, true, generatedExpr, storageType)
-					i += strings.Count(exprStr, " ") + 3
-				}
-			}
+// resolveReference looks up the referenced table among the models of the column
+func (c *Column) resolveReference(def *constraintDef) (*foreignkey.References, error) {
+	var foreignTable any
+	for _, model := range c.models {
+		structName := reflectutil.GetStructName(model)
+		if strings.EqualFold(def.reference.table, structName) ||
+			def.reference.table == utils.ToSnakeCase(structName) ||
+			def.reference.table == reflectutil.GetTableName(model) {
+			foreignTable = model
+			break
 		}
+	}
+	if foreignTable == nil {
+		return nil, newTagError(c.name, def.offset,
+			fmt.Sprintf("referenced table '%s' isn't among the provided foreign tables", def.reference.table))
+	}
 
+	fieldNames := reflectutil.GetStructFieldsNames(foreignTable)
+	foreignColumns := make([]string, 0, len(def.reference.columns))
+	for _, col := range def.reference.columns {
+		idx := slices.IndexFunc(fieldNames, func(field string) bool {
+			return strings.EqualFold(col, field) || col == utils.ToSnakeCase(field)
+		})
+		if idx == -1 {
+			return nil, newTagError(c.name, def.offset,
+				fmt.Sprintf("column '%s' not found in referenced table '%s'", col, def.reference.table))
+		}
+		foreignColumns = append(foreignColumns, fieldNames[idx])
+	}
+
+	ref := foreignkey.NewColumnReferences(c.name, foreignTable, foreignColumns).
+		OnDelete(def.reference.onDelete).
+		OnUpdate(def.reference.onUpdate).
+		Match(def.reference.match)
+	if def.reference.deferrable != nil {
+		ref.Deferrable(*def.reference.deferrable)
+	}
+	if def.reference.notDeferrable != nil {
+		ref.NotDeferrable(*def.reference.notDeferrable)
+	}
+	return ref, nil
+}
+
+// Constraint is a constraint added to the column, only the fields
+// belonging to its Kind are set
+type Constraint struct {
+	Kind          constraintToken
+	Name          string
+	SortOrder     sortorder.SortOrder    // PRIMARY KEY
+	Autoincrement bool                   // PRIMARY KEY
+	Conflict      string                 // PRIMARY KEY, NOT NULL, UNIQUE
+	Expression    string                 // CHECK, DEFAULT, GENERATED
+	Collation     string                 // COLLATE
+	Always        bool                   // GENERATED
+	Storage       generated.StorageType  // GENERATED
+	Reference     *foreignkey.References // REFERENCES
+
+	sql string
+}
+
+// Build returns the SQL of the constraint including its CONSTRAINT name
+func (c *Constraint) Build() string {
+	if c.Name == "" {
+		return c.sql
 	}
+	return fmt.Sprintf("CONSTRAINT %s %s", c.Name, c.sql)
+}
+
+func (c *Column) addConstraint(constraint *Constraint, sql string) *Column {
+	constraint.sql = sql
+	c.constraints = append(c.constraints, constraint)
+	return c
 }
 
 // PrimaryKey adds a PRIMARY KEY constraint to the column
@@ -265,40 +232,27 @@ func (c *Column) PrimaryKey(constraintName string, sortOrder sortorder.SortOrder
 	c.hasPrimaryKey = true
 	c.hasAutoincrement = autoincrement
 
-	// Add constraint
-	if constraintName == "" {
-		c.constraint = append(c.constraint, primarykey.NewColumnPrimaryKey(sortOrder).
-			Conflict(conflict).
-			Autoincrement(autoincrement).Build(),
-		)
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				primarykey.NewColumnPrimaryKey(sortOrder).
-					Conflict(conflict).
-					Autoincrement(autoincrement).Build(),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:          PRIMARY_KEY,
+		Name:          constraintName,
+		SortOrder:     sortOrder,
+		Autoincrement: autoincrement,
+		Conflict:      conflict,
+	}, primarykey.NewColumnPrimaryKey(sortOrder).
+		Conflict(conflict).
+		Autoincrement(autoincrement).Build(),
+	)
 }
 
 // NotNull adds a NOT NULL constraint to the column
 func (c *Column) NotNull(constraintName string, conflict string) *Column {
 	c.hasNotNull = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, notnull.NewNotNull(conflict))
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				notnull.NewNotNull(conflict),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:     NOT_NULL,
+		Name:     constraintName,
+		Conflict: conflict,
+	}, notnull.NewNotNull(conflict))
 }
 
 // Unique adds a UNIQUE constraint to the column
@@ -310,34 +264,30 @@ func (c *Column) Unique(constraintName string, conflict string) *Column {
 
 	c.hasUnique = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, unique.NewColumnUnique(conflict))
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				unique.NewColumnUnique(conflict),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:     UNIQUE,
+		Name:     constraintName,
+		Conflict: conflict,
+	}, unique.NewColumnUnique(conflict))
 }
 
 // Check adds a CHECK constraint to the column
 func (c *Column) Check(constraintName string, expr *expr.Expression) *Column {
 	c.hasCheck = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, check.NewCheck(expr).Build())
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				check.NewCheck(expr).Build(),
-			),
-		)
+	return c.addConstraint(&Constraint{
+		Kind:       CHECK,
+		Name:       constraintName,
+		Expression: expr.Inline(),
+	}, check.NewCheck(expr).Build())
+}
+
+// Enum adds a CHECK constraint limiting the column to the given SQL literals
+func (c *Column) Enum(constraintName string, values []string) *Column {
+	if len(values) == 0 {
+		panic(fmt.Errorf("enum column '%s' has no allowed values", c.name))
 	}
-	return c
+	return c.Check(constraintName, expr.NewExpression(enum.NewEnumCheck(c.name, values)))
 }
 
 // Default adds a DEFAULT constraint to the column
@@ -354,61 +304,56 @@ func (c *Column) Default(constraintName string, content string) *Column {
 
 	c.hasDefault = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, defaultConstr.ParseDefault(content))
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				defaultConstr.ParseDefault(content),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:       DEFAULT,
+		Name:       constraintName,
+		Expression: content,
+	}, defaultConstr.ParseDefault(content))
 }
 
 // Collate adds a COLLATE constraint to the column
 func (c *Column) Collate(constraintName string, name string) *Column {
 	c.hasCollate = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, collate.NewCollate(name))
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				collate.NewCollate(name),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:      COLLATE,
+		Name:      constraintName,
+		Collation: name,
+	}, collate.NewCollate(name))
 }
 
-// ForeignKey adds a REFERENCES foreign key constraint to the column
+// ForeignKey adds a REFERENCES foreign key constraint to the column,
+// content is the REFERENCES clause in the tag syntax
 func (c *Column) ForeignKey(constraintName string, content string) *Column {
-	// Cannot have both FOREIGN KEY and GENERATED
-	if c.hasGenerated {
-		panic("GENERATED column cannot be FOREIGN KEY")
+	defs, err := parseTag(c.name, content)
+	if err != nil {
+		panic(err)
+	}
+	if len(defs) != 1 || defs[0].kind != REFERENCES {
+		panic(fmt.Errorf("foreign key of column '%s' has to be a single REFERENCES clause", c.name))
 	}
 
-	c.hasForeignKey = true
-
-	ref, err := foreignkey.ParseReference(c.name, c.models, content)
+	ref, err := c.resolveReference(defs[0])
 	if err != nil {
 		panic(err)
 	}
+	return c.References(constraintName, ref)
+}
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, ref.Build())
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				ref.Build(),
-			),
-		)
+// References adds a REFERENCES foreign key constraint to the column
+func (c *Column) References(constraintName string, ref *foreignkey.References) *Column {
+	// Cannot have both FOREIGN KEY and GENERATED
+	if c.hasGenerated {
+		panic("GENERATED column cannot be FOREIGN KEY")
 	}
-	return c
+
+	c.hasForeignKey = true
+
+	return c.addConstraint(&Constraint{
+		Kind:      REFERENCES,
+		Name:      constraintName,
+		Reference: ref,
+	}, ref.Build())
 }
 
 // Generated adds a GENERATED ALWAYS constraint for computed columns
@@ -435,96 +380,87 @@ func (c *Column) Generated(constraintName string, always bool, expr *expr.Expres
 
 	c.hasGenerated = true
 
-	if constraintName == "" {
-		c.constraint = append(c.constraint, generated.NewGenerated(always, expr, storageType))
-	} else {
-		c.constraint = append(c.constraint,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				generated.NewGenerated(always, expr, storageType),
-			),
-		)
-	}
-	return c
+	return c.addConstraint(&Constraint{
+		Kind:       GENERATED,
+		Name:       constraintName,
+		Expression: expr.Inline(),
+		Always:     always,
+		Storage:    storageType,
+	}, generated.NewGenerated(always, expr, storageType))
 }
 
-func (c *Column) Build() string {
-	constraintStr := ""
-	if len(c.constraint) > 0 {
-		constraintStr = " " + strings.Join(c.constraint, " ")
-	}
-
-	return fmt.Sprintf("%s%s%s", c.name, " "+c.colType.String(), constraintStr)
+// ColumnIndex is an index declared on a column, columns sharing
+// the index name make up a single multi-column index
+type ColumnIndex struct {
+	Name      string
+	Unique    bool
+	SortOrder sortorder.SortOrder
 }
 
-// Helper functions
-func parseConflictClause(str string) string {
-	switch strings.ToUpper(str) {
-	case "ROLLBACK", "ABORT", "FAIL", "IGNORE", "REPLACE":
-		return str
-	default:
-		return ""
-	}
+// Index declares an index containing the column
+func (c *Column) Index(indexName string, unique bool, sortOrder sortorder.SortOrder) *Column {
+	c.indexes = append(c.indexes, &ColumnIndex{
+		Name:      indexName,
+		Unique:    unique,
+		SortOrder: sortOrder,
+	})
+	return c
 }
 
-// Helper to find a balanced parentheses expression
-func findBalancedParentheses(tokens []string, startIndex int) string {
-	if startIndex >= len(tokens) || tokens[startIndex] != "(" {
-		return ""
-	}
-
-	level := 0
-	var builder strings.Builder
-
-	for i := startIndex; i < len(tokens); i++ {
-		token := tokens[i]
-
-		// Count opening parentheses
-		level += strings.Count(token, "(")
+// Name returns the name of the column
+func (c *Column) Name() string {
+	return c.name
+}
 
-		// Count closing parentheses
-		level -= strings.Count(token, ")")
+// Type returns the SQLite type of the column
+func (c *Column) Type() types.SQLiteType {
+	return c.colType
+}
 
-		builder.WriteString(token)
-		builder.WriteString(" ")
+// Constraints returns the constraints of the column in the order they were added
+func (c *Column) Constraints() []*Constraint {
+	return c.constraints
+}
 
-		if level == 0 {
-			// We've found the matching closing parenthesis
-			return strings.TrimSpace(builder.String())
-		}
-	}
+// Indexes returns the indexes declared on the column
+func (c *Column) Indexes() []*ColumnIndex {
+	return c.indexes
+}
 
-	// No balanced parentheses found
-	return ""
+// IsPrimaryKey reports whether the column has a PRIMARY KEY constraint
+func (c *Column) IsPrimaryKey() bool {
+	return c.hasPrimaryKey
 }
 
-// Helper to extract the complete REFERENCES clause
-func extractReferencesClause(tokens []string) string {
-	if len(tokens) < 2 || strings.ToUpper(tokens[0]) != "REFERENCES" {
-		return ""
-	}
+// IsAutoincrement reports whether the column is an AUTOINCREMENT primary key
+func (c *Column) IsAutoincrement() bool {
+	return c.hasAutoincrement
+}
 
-	var builder strings.Builder
-	builder.WriteString(tokens[0]) // REFERENCES
+// IsNotNull reports whether the column can't hold NULL, primary keys included
+func (c *Column) IsNotNull() bool {
+	return c.hasNotNull
+}
 
-	inParentheses := false
+// IsUnique reports whether the column has a UNIQUE constraint
+func (c *Column) IsUnique() bool {
+	return c.hasUnique
+}
 
-	for i := 1; i < len(tokens); i++ {
-		token := tokens[i]
+// IsGenerated reports whether the column is computed
+func (c *Column) IsGenerated() bool {
+	return c.hasGenerated
+}
 
-		if strings.HasPrefix(token, "(") {
-			inParentheses = true
-		} else if strings.HasSuffix(token, ")") {
-			inParentheses = false
-		} else if !inParentheses && isConstraintKeyword(token) {
-			// Stop at the beginning of a new constraint keyword
-			// except for the ones that could be part of a foreign key clause
-			break
+func (c *Column) Build() string {
+	constraintStr := ""
+	if len(c.constraints) > 0 {
+		parts := make([]string, len(c.constraints))
+		for i, constraint := range c.constraints {
+			parts[i] = constraint.Build()
 		}
-
-		builder.WriteString(" ")
-		builder.WriteString(token)
+		constraintStr = " " + strings.Join(parts, " ")
 	}
 
-	return builder.String()
+	return fmt.Sprintf("%s%s%s", c.name, " "+c.colType.String(), constraintStr)
 }
@@ -0,0 +1,55 @@
+package column
+
+import (
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// FieldColumn is a struct field with its column as statements writing the model need it.
+// The tag is parsed without resolving REFERENCES, so the referenced models aren't needed.
+type FieldColumn struct {
+	Field         string // Go field name
+	Name          string // column name
+	PrimaryKey    bool
+	Autoincrement bool
+	Generated     bool
+}
+
+// Writable reports whether the column takes a value from INSERT and UPDATE,
+// SQLite computes generated and AUTOINCREMENT columns itself
+func (f FieldColumn) Writable() bool {
+	return !f.Generated && !f.Autoincrement
+}
+
+// FieldColumns returns the columns of the fields of the model in the field order
+func FieldColumns(model any) ([]FieldColumn, error) {
+	var columns []FieldColumn
+	for _, field := range reflectutil.GetStructFields(model) {
+		tag, ok := field.Tag.Lookup("sqlofi")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		col := FieldColumn{
+			Field: field.Name,
+			Name:  utils.ToSnakeCase(field.Name),
+		}
+
+		defs, err := parseFieldTag(col.Name, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, def := range defs {
+			switch def.kind {
+			case PRIMARY_KEY:
+				col.PrimaryKey = true
+				col.Autoincrement = def.autoincrement
+			case GENERATED:
+				col.Generated = true
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
@@ -0,0 +1,451 @@
+package column
+
+import (
+	"fmt"
+	"strings"
+
+	generated "github.com/Nevoral/sqlofi/internal/sqlite/Generated"
+	sortorder "github.com/Nevoral/sqlofi/internal/sqlite/SortOrder"
+)
+
+// The key=value tag syntax is an alternative to the SQL-like one:
+//
+//	`sqlofi:"pk,autoinc;notnull;default=0;check=price >= 0;fk=Category.Id,ondelete=cascade;index=idx_x"`
+//
+// Constraints are separated by ";", the first option of a constraint is its kind
+// and the options after "," modify it. An option is a key with an optional "=value",
+// only the first "=" separates the key from the value.
+//
+// Separators inside parentheses or quotes don't split, so "check=coalesce(a, b) > 0"
+// needs no escaping. Anywhere else a backslash escapes the next character:
+// "\;", "\," and "\\" stand for ";", "," and "\". Struct tag values are Go string
+// literals, so inside a struct tag the backslash itself is written twice: `sqlofi:"default=a\\;b"`.
+//
+// Constraint kinds and their options:
+//
+//	pk             asc, desc, autoinc, onconflict=<resolution>, name=<constraint>
+//	notnull        onconflict=<resolution>, name=<constraint>
+//	unique         onconflict=<resolution>, name=<constraint>
+//	check=<expr>   name=<constraint>
+//	default=<val>  name=<constraint>
+//	collate=<name> name=<constraint>
+//	fk=<Table>[.<Column>]  ondelete=<action>, onupdate=<action>, match=<name>,
+//	               deferrable[=deferred|immediate], notdeferrable[=deferred|immediate], name=<constraint>
+//	generated=<expr>  stored, virtual, name=<constraint>
+//	index=<name>   unique, asc, desc
+
+// keyValueKinds maps the constraint keys of the key=value syntax to constraint kinds
+var keyValueKinds = map[string]constraintToken{
+	"pk":         PRIMARY_KEY,
+	"primarykey": PRIMARY_KEY,
+	"notnull":    NOT_NULL,
+	"unique":     UNIQUE,
+	"check":      CHECK,
+	"default":    DEFAULT,
+	"collate":    COLLATE,
+	"fk":         REFERENCES,
+	"references": REFERENCES,
+	"generated":  GENERATED,
+	"index":      INDEX,
+}
+
+// tagPart is a piece of a key=value tag together with its offset inside the tag
+type tagPart struct {
+	text   string
+	offset int
+}
+
+// keyValueOption is a single key[=value] option of a key=value tag
+type keyValueOption struct {
+	key      string
+	value    string
+	hasValue bool
+	offset   int
+}
+
+// isKeyValueTag reports whether the tag uses the key=value syntax,
+// that is whether it starts with one of the key=value constraint keys
+func isKeyValueTag(tag string) bool {
+	items := splitTag(tagPart{text: tag}, ';')
+	if len(items) == 0 {
+		return false
+	}
+	options := splitTag(items[0], ',')
+	if len(options) == 0 {
+		return false
+	}
+
+	key, _, _ := strings.Cut(options[0].text, "=")
+	key = strings.TrimSpace(key)
+	if strings.ContainsAny(key, " \t\r\n") {
+		return false
+	}
+	_, ok := keyValueKinds[strings.ToLower(key)]
+	return ok
+}
+
+// parseKeyValueTag parses the key=value tag syntax of a column
+func parseKeyValueTag(column, tag string) ([]*constraintDef, error) {
+	var defs []*constraintDef
+
+	for _, item := range splitTag(tagPart{text: tag}, ';') {
+		if strings.TrimSpace(item.text) == "" {
+			continue
+		}
+
+		var options []keyValueOption
+		for _, part := range splitTag(item, ',') {
+			options = append(options, parseKeyValueOption(part))
+		}
+
+		def, err := keyValueConstraint(column, options)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func parseKeyValueOption(part tagPart) keyValueOption {
+	key, value, hasValue := cutUnescaped(part.text, '=')
+	leading := len(key) - len(strings.TrimLeft(key, " \t\r\n"))
+	return keyValueOption{
+		key:      strings.ToLower(unescapeTag(strings.TrimSpace(key))),
+		value:    unescapeTag(strings.TrimSpace(value)),
+		hasValue: hasValue,
+		offset:   part.offset + leading,
+	}
+}
+
+func keyValueConstraint(column string, options []keyValueOption) (*constraintDef, error) {
+	head := options[0]
+	kind, ok := keyValueKinds[head.key]
+	if !ok {
+		return nil, newTagError(column, head.offset, fmt.Sprintf("unknown constraint %q", head.key))
+	}
+
+	def := &constraintDef{
+		kind:   kind,
+		offset: head.offset,
+	}
+
+	switch kind {
+	case CHECK, DEFAULT, COLLATE, REFERENCES, GENERATED, INDEX:
+		if head.value == "" {
+			return nil, newTagError(column, head.offset, fmt.Sprintf("%s needs a value", head.key))
+		}
+	default:
+		if head.hasValue {
+			return nil, newTagError(column, head.offset, fmt.Sprintf("%s doesn't take a value", head.key))
+		}
+	}
+
+	switch kind {
+	case CHECK:
+		def.expression = head.value
+	case DEFAULT:
+		def.expression = head.value
+	case COLLATE:
+		def.collation = head.value
+	case REFERENCES:
+		table, col, hasColumn := strings.Cut(head.value, ".")
+		def.reference = &referenceDef{table: unquoteIdentifier(strings.TrimSpace(table))}
+		if hasColumn {
+			def.reference.columns = []string{unquoteIdentifier(strings.TrimSpace(col))}
+		}
+	case GENERATED:
+		def.expression = head.value
+		def.always = true
+	case INDEX:
+		def.name = head.value
+	}
+
+	for _, opt := range options[1:] {
+		if err := applyKeyValueOption(column, def, opt); err != nil {
+			return nil, err
+		}
+	}
+	return def, nil
+}
+
+func applyKeyValueOption(column string, def *constraintDef, opt keyValueOption) error {
+	invalid := func() error {
+		return newTagError(column, opt.offset, fmt.Sprintf("option %q isn't valid for %s", opt.key, def.kind))
+	}
+
+	switch opt.key {
+	case "name":
+		if def.kind == INDEX || opt.value == "" {
+			return invalid()
+		}
+		def.name = opt.value
+
+	case "asc", "desc":
+		if def.kind != PRIMARY_KEY && def.kind != INDEX {
+			return invalid()
+		}
+		def.sortOrder = sortorder.SortOrder(strings.ToUpper(opt.key))
+
+	case "autoinc", "autoincrement":
+		if def.kind != PRIMARY_KEY {
+			return invalid()
+		}
+		def.autoincrement = true
+
+	case "onconflict":
+		if def.kind != PRIMARY_KEY && def.kind != NOT_NULL && def.kind != UNIQUE {
+			return invalid()
+		}
+		resolution := strings.ToUpper(opt.value)
+		switch resolution {
+		case "ROLLBACK", "ABORT", "FAIL", "IGNORE", "REPLACE":
+			def.conflict = "ON CONFLICT " + resolution
+		default:
+			return newTagError(column, opt.offset, fmt.Sprintf("invalid conflict resolution %q", opt.value))
+		}
+
+	case "ondelete", "onupdate":
+		if def.kind != REFERENCES {
+			return invalid()
+		}
+		action := strings.ToUpper(strings.ReplaceAll(opt.value, "_", " "))
+		switch action {
+		case "CASCADE", "SET NULL", "SET DEFAULT", "RESTRICT", "NO ACTION":
+		default:
+			return newTagError(column, opt.offset, fmt.Sprintf("invalid row action %q", opt.value))
+		}
+		if opt.key == "ondelete" {
+			def.reference.onDelete = action
+		} else {
+			def.reference.onUpdate = action
+		}
+
+	case "match":
+		if def.kind != REFERENCES || opt.value == "" {
+			return invalid()
+		}
+		def.reference.match = opt.value
+
+	case "deferrable", "notdeferrable":
+		if def.kind != REFERENCES {
+			return invalid()
+		}
+		var initially string
+		switch strings.ToLower(opt.value) {
+		case "":
+		case "deferred":
+			initially = "INITIALLY DEFERRED"
+		case "immediate":
+			initially = "INITIALLY IMMEDIATE"
+		default:
+			return newTagError(column, opt.offset, fmt.Sprintf("invalid deferrable mode %q", opt.value))
+		}
+		if opt.key == "deferrable" {
+			def.reference.deferrable = &initially
+		} else {
+			def.reference.notDeferrable = &initially
+		}
+
+	case "stored", "virtual":
+		if def.kind != GENERATED {
+			return invalid()
+		}
+		def.storage = generated.StorageType(strings.ToUpper(opt.key))
+
+	case "unique":
+		if def.kind != INDEX {
+			return invalid()
+		}
+		def.unique = true
+
+	default:
+		return invalid()
+	}
+	return nil
+}
+
+// splitTag splits a part of a key=value tag on the separator, ignoring separators
+// that are escaped, quoted or inside parentheses
+func splitTag(part tagPart, sep byte) []tagPart {
+	var (
+		parts []tagPart
+		start int
+		depth int
+		quote byte
+	)
+
+	for i := 0; i < len(part.text); i++ {
+		ch := part.text[i]
+		switch {
+		case ch == '\\':
+			i++
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth > 0 {
+				depth--
+			}
+		case ch == sep && depth == 0:
+			parts = append(parts, tagPart{text: part.text[start:i], offset: part.offset + start})
+			start = i + 1
+		}
+	}
+	return append(parts, tagPart{text: part.text[start:], offset: part.offset + start})
+}
+
+// cutUnescaped slices s around the first unescaped occurrence of sep
+func cutUnescaped(s string, sep byte) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unescapeTag removes the backslash escapes of a key=value tag
+func unescapeTag(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		builder.WriteByte(s[i])
+	}
+	return builder.String()
+}
+
+// escapeTag escapes the characters of a value that would otherwise split a key=value tag
+func escapeTag(s string) string {
+	var (
+		builder strings.Builder
+		depth   int
+		quote   byte
+	)
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\\':
+			builder.WriteByte('\\')
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth > 0 {
+				depth--
+			}
+		case (ch == ';' || ch == ',') && depth == 0:
+			builder.WriteByte('\\')
+		}
+		builder.WriteByte(ch)
+	}
+	return builder.String()
+}
+
+// ConvertTag rewrites a tag written in the SQL-like syntax into the key=value syntax
+func ConvertTag(tag string) (string, error) {
+	if isKeyValueTag(tag) {
+		return tag, nil
+	}
+
+	defs, err := parseTag("", tag)
+	if err != nil {
+		return "", err
+	}
+
+	items := make([]string, 0, len(defs))
+	for _, def := range defs {
+		items = append(items, def.keyValue())
+	}
+	return strings.Join(items, ";"), nil
+}
+
+// keyValue formats the constraint definition in the key=value syntax
+func (d *constraintDef) keyValue() string {
+	var options []string
+
+	switch d.kind {
+	case PRIMARY_KEY:
+		options = append(options, "pk")
+		if d.sortOrder != sortorder.UNSORTED {
+			options = append(options, strings.ToLower(d.sortOrder.String()))
+		}
+		if d.autoincrement {
+			options = append(options, "autoinc")
+		}
+	case NOT_NULL:
+		options = append(options, "notnull")
+	case UNIQUE:
+		options = append(options, "unique")
+	case CHECK:
+		options = append(options, "check="+escapeTag(d.expression))
+	case DEFAULT:
+		options = append(options, "default="+escapeTag(d.expression))
+	case COLLATE:
+		options = append(options, "collate="+escapeTag(d.collation))
+	case REFERENCES:
+		fk := d.reference.table
+		if len(d.reference.columns) > 0 {
+			fk += "." + d.reference.columns[0]
+		}
+		options = append(options, "fk="+escapeTag(fk))
+		if d.reference.onDelete != "" {
+			options = append(options, "ondelete="+strings.ToLower(d.reference.onDelete))
+		}
+		if d.reference.onUpdate != "" {
+			options = append(options, "onupdate="+strings.ToLower(d.reference.onUpdate))
+		}
+		if d.reference.match != "" {
+			options = append(options, "match="+escapeTag(d.reference.match))
+		}
+		if d.reference.deferrable != nil {
+			options = append(options, deferrableOption("deferrable", *d.reference.deferrable))
+		}
+		if d.reference.notDeferrable != nil {
+			options = append(options, deferrableOption("notdeferrable", *d.reference.notDeferrable))
+		}
+	case GENERATED:
+		options = append(options, "generated="+escapeTag(d.expression))
+		if d.storage != generated.NO_STORAGE {
+			options = append(options, strings.ToLower(d.storage.String()))
+		}
+	}
+
+	if d.conflict != "" {
+		options = append(options, "onconflict="+strings.ToLower(strings.TrimPrefix(d.conflict, "ON CONFLICT ")))
+	}
+	if d.name != "" {
+		options = append(options, "name="+escapeTag(d.name))
+	}
+	return strings.Join(options, ",")
+}
+
+func deferrableOption(key, initially string) string {
+	switch initially {
+	case "INITIALLY DEFERRED":
+		return key + "=deferred"
+	case "INITIALLY IMMEDIATE":
+		return key + "=immediate"
+	}
+	return key
+}
@@ -0,0 +1,238 @@
+package column
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF        tokenKind = iota
+	tokWord                 // keyword or bare identifier
+	tokIdentifier           // "quoted", `quoted` or [quoted] identifier
+	tokString               // 'string literal'
+	tokNumber               // 12, 1.5, 1e10, 0xFF
+	tokLParen               // (
+	tokRParen               // )
+	tokComma                // ,
+	tokOperator             // any other punctuation: + - * / || <= ...
+)
+
+// token is a single lexeme of a sqlofi tag
+type token struct {
+	kind  tokenKind
+	text  string
+	start int // byte offset of the first character
+	end   int // byte offset after the last character
+}
+
+// is reports whether the token is the given keyword, keywords are case-insensitive
+func (t token) is(keyword string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of tag"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// span is a byte range of the tag
+type span struct {
+	start int
+	end   int
+}
+
+// lexer splits a sqlofi tag into tokens, dropping whitespace and comments
+type lexer struct {
+	column   string
+	input    string
+	pos      int
+	tokens   []token
+	comments []span
+}
+
+func tokenize(column, input string) ([]token, []span, error) {
+	l := &lexer{
+		column: column,
+		input:  input,
+	}
+	if err := l.run(); err != nil {
+		return nil, nil, err
+	}
+	return l.tokens, l.comments, nil
+}
+
+func (l *lexer) run() error {
+	for {
+		l.skipWhitespace()
+		if l.pos >= len(l.input) {
+			l.emit(tokEOF, l.pos)
+			return nil
+		}
+
+		start := l.pos
+		ch := l.input[l.pos]
+
+		switch {
+		case strings.HasPrefix(l.input[l.pos:], "--"):
+			end := strings.IndexByte(l.input[l.pos:], '\n')
+			if end == -1 {
+				l.pos = len(l.input)
+			} else {
+				l.pos += end + 1
+			}
+			l.comments = append(l.comments, span{start, l.pos})
+
+		case strings.HasPrefix(l.input[l.pos:], "/*"):
+			end := strings.Index(l.input[l.pos+2:], "*/")
+			if end == -1 {
+				return newTagError(l.column, start, "unterminated comment")
+			}
+			l.pos += end + 4
+			l.comments = append(l.comments, span{start, l.pos})
+
+		case ch == '\'':
+			if err := l.quoted('\'', '\''); err != nil {
+				return err
+			}
+			l.emit(tokString, start)
+
+		case ch == '"':
+			if err := l.quoted('"', '"'); err != nil {
+				return err
+			}
+			l.emit(tokIdentifier, start)
+
+		case ch == '`':
+			if err := l.quoted('`', '`'); err != nil {
+				return err
+			}
+			l.emit(tokIdentifier, start)
+
+		case ch == '[':
+			if err := l.quoted('[', ']'); err != nil {
+				return err
+			}
+			l.emit(tokIdentifier, start)
+
+		case ch == '(':
+			l.pos++
+			l.emit(tokLParen, start)
+
+		case ch == ')':
+			l.pos++
+			l.emit(tokRParen, start)
+
+		case ch == ',':
+			l.pos++
+			l.emit(tokComma, start)
+
+		case isDigit(ch) || (ch == '.' && l.pos+1 < len(l.input) && isDigit(l.input[l.pos+1])):
+			l.number()
+			l.emit(tokNumber, start)
+
+		case isWordStart(ch):
+			for l.pos < len(l.input) && isWordPart(l.input[l.pos]) {
+				l.pos++
+			}
+			l.emit(tokWord, start)
+
+		default:
+			l.operator()
+			l.emit(tokOperator, start)
+		}
+	}
+}
+
+func (l *lexer) emit(kind tokenKind, start int) {
+	l.tokens = append(l.tokens, token{
+		kind:  kind,
+		text:  l.input[start:l.pos],
+		start: start,
+		end:   l.pos,
+	})
+}
+
+func (l *lexer) skipWhitespace() {
+	for l.pos < len(l.input) && strings.IndexByte(" \t\r\n\f", l.input[l.pos]) != -1 {
+		l.pos++
+	}
+}
+
+// quoted consumes a quoted lexeme, a doubled closing character is an escaped one
+func (l *lexer) quoted(open, close byte) error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.input) {
+		if l.input[l.pos] == close {
+			if open == close && l.pos+1 < len(l.input) && l.input[l.pos+1] == close {
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			return nil
+		}
+		l.pos++
+	}
+	return newTagError(l.column, start, fmt.Sprintf("unterminated quote %c", open))
+}
+
+func (l *lexer) number() {
+	if strings.HasPrefix(l.input[l.pos:], "0x") || strings.HasPrefix(l.input[l.pos:], "0X") {
+		l.pos += 2
+		for l.pos < len(l.input) && isHexDigit(l.input[l.pos]) {
+			l.pos++
+		}
+		return
+	}
+
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		next := l.pos + 1
+		if next < len(l.input) && (l.input[next] == '+' || l.input[next] == '-') {
+			next++
+		}
+		if next < len(l.input) && isDigit(l.input[next]) {
+			l.pos = next
+			for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+				l.pos++
+			}
+		}
+	}
+}
+
+// operators lists the multi character operators, "->>" comes before "->" so the longest one wins
+var operators = []string{"||", "<<", ">>", "<=", ">=", "==", "!=", "<>", "->>", "->"}
+
+func (l *lexer) operator() {
+	rest := l.input[l.pos:]
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return
+		}
+	}
+	l.pos++
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func isWordStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= 0x80
+}
+
+func isWordPart(ch byte) bool {
+	return isWordStart(ch) || isDigit(ch) || ch == '$'
+}
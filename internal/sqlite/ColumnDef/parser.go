@@ -0,0 +1,480 @@
+package column
+
+import (
+	"fmt"
+	"strings"
+
+	generated "github.com/Nevoral/sqlofi/internal/sqlite/Generated"
+	sortorder "github.com/Nevoral/sqlofi/internal/sqlite/SortOrder"
+)
+
+// TagError reports a malformed sqlofi tag, Offset is the byte offset inside the tag
+type TagError struct {
+	Column string
+	Offset int
+	Msg    string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("sqlofi tag of column '%s' at offset %d: %s", e.Column, e.Offset, e.Msg)
+}
+
+func newTagError(column string, offset int, msg string) *TagError {
+	return &TagError{
+		Column: column,
+		Offset: offset,
+		Msg:    msg,
+	}
+}
+
+// constraintDef is a single column constraint parsed from a tag
+type constraintDef struct {
+	kind   constraintToken
+	name   string
+	offset int
+
+	sortOrder     sortorder.SortOrder   // PRIMARY KEY
+	autoincrement bool                  // PRIMARY KEY
+	conflict      string                // PRIMARY KEY, NOT NULL, UNIQUE
+	expression    string                // CHECK, DEFAULT, GENERATED
+	collation     string                // COLLATE
+	always        bool                  // GENERATED
+	storage       generated.StorageType // GENERATED
+	reference     *referenceDef         // REFERENCES
+	unique        bool                  // INDEX
+}
+
+// referenceDef is the foreign-key-clause of a REFERENCES constraint
+type referenceDef struct {
+	table         string
+	columns       []string
+	onDelete      string
+	onUpdate      string
+	match         string
+	deferrable    *string
+	notDeferrable *string
+}
+
+// parser turns the tokens of a sqlofi tag into constraint definitions.
+// The grammar follows the SQLite column-constraint syntax:
+//
+//	[CONSTRAINT name] PRIMARY KEY [ASC|DESC] [conflict-clause] [AUTOINCREMENT]
+//	[CONSTRAINT name] NOT NULL [conflict-clause]
+//	[CONSTRAINT name] UNIQUE [conflict-clause]
+//	[CONSTRAINT name] CHECK (expr)
+//	[CONSTRAINT name] DEFAULT (expr) | literal | signed-number
+//	[CONSTRAINT name] COLLATE name
+//	[CONSTRAINT name] REFERENCES table [(column, ...)] [ON DELETE|UPDATE action] [MATCH name] [[NOT] DEFERRABLE [INITIALLY DEFERRED|IMMEDIATE]]
+//	[CONSTRAINT name] [GENERATED ALWAYS] AS (expr) [STORED|VIRTUAL]
+type parser struct {
+	column   string
+	input    string
+	tokens   []token
+	comments []span
+	pos      int
+}
+
+// parseFieldTag parses the tag of a struct field in the key=value or the SQL-like syntax
+func parseFieldTag(column, tag string) ([]*constraintDef, error) {
+	if isKeyValueTag(tag) {
+		return parseKeyValueTag(column, tag)
+	}
+	return parseTag(column, tag)
+}
+
+// parseTag parses the SQL-like tag syntax of a column
+func parseTag(column, tag string) ([]*constraintDef, error) {
+	tokens, comments, err := tokenize(column, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		column:   column,
+		input:    tag,
+		tokens:   tokens,
+		comments: comments,
+	}
+
+	var defs []*constraintDef
+	for p.peek().kind != tokEOF {
+		def, err := p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return newTagError(p.column, tok.start, fmt.Sprintf(format, args...))
+}
+
+// accept consumes the next token if it is the keyword
+func (p *parser) accept(keyword string) bool {
+	if p.peek().is(keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the keywords in order or fails on the first mismatch
+func (p *parser) expect(keywords ...string) error {
+	for _, keyword := range keywords {
+		tok := p.next()
+		if !tok.is(keyword) {
+			return p.errorf(tok, "expected %s, found %s", keyword, tok)
+		}
+	}
+	return nil
+}
+
+// name consumes a bare or quoted identifier
+func (p *parser) name(what string) (token, error) {
+	tok := p.next()
+	if tok.kind != tokWord && tok.kind != tokIdentifier {
+		return tok, p.errorf(tok, "expected %s, found %s", what, tok)
+	}
+	return tok, nil
+}
+
+// source returns the tag text between two offsets with comments blanked out
+func (p *parser) source(start, end int) string {
+	var builder strings.Builder
+	pos := start
+	for _, comment := range p.comments {
+		if comment.end <= start || comment.start >= end {
+			continue
+		}
+		builder.WriteString(p.input[pos:comment.start])
+		builder.WriteString(" ")
+		pos = comment.end
+	}
+	builder.WriteString(p.input[pos:end])
+	return strings.TrimSpace(builder.String())
+}
+
+// parenthesized consumes a balanced ( ... ) group and returns the text inside it
+func (p *parser) parenthesized(what string) (string, error) {
+	open := p.next()
+	if open.kind != tokLParen {
+		return "", p.errorf(open, "expected ( after %s, found %s", what, open)
+	}
+
+	level := 1
+	for {
+		tok := p.next()
+		switch tok.kind {
+		case tokLParen:
+			level++
+		case tokRParen:
+			level--
+			if level == 0 {
+				inner := p.source(open.end, tok.start)
+				if inner == "" {
+					return "", p.errorf(open, "empty expression in %s", what)
+				}
+				return inner, nil
+			}
+		case tokEOF:
+			return "", p.errorf(open, "unbalanced parentheses in %s", what)
+		}
+	}
+}
+
+// conflictClause consumes an optional ON CONFLICT resolution
+func (p *parser) conflictClause() (string, error) {
+	if !p.peek().is("ON") || !p.peekAt(1).is("CONFLICT") {
+		return "", nil
+	}
+	p.pos += 2
+
+	tok := p.next()
+	for _, resolution := range []string{"ROLLBACK", "ABORT", "FAIL", "IGNORE", "REPLACE"} {
+		if tok.is(resolution) {
+			return "ON CONFLICT " + resolution, nil
+		}
+	}
+	return "", p.errorf(tok, "expected conflict resolution, found %s", tok)
+}
+
+func (p *parser) parseConstraint() (*constraintDef, error) {
+	def := &constraintDef{offset: p.peek().start}
+
+	if p.accept("CONSTRAINT") {
+		tok, err := p.name("constraint name")
+		if err != nil {
+			return nil, err
+		}
+		def.name = tok.text
+	}
+
+	tok := p.next()
+	switch {
+	case tok.is("PRIMARY"):
+		if err := p.expect("KEY"); err != nil {
+			return nil, err
+		}
+		def.kind = PRIMARY_KEY
+		if p.accept("ASC") {
+			def.sortOrder = sortorder.ASC
+		} else if p.accept("DESC") {
+			def.sortOrder = sortorder.DESC
+		}
+		conflict, err := p.conflictClause()
+		if err != nil {
+			return nil, err
+		}
+		def.conflict = conflict
+		def.autoincrement = p.accept("AUTOINCREMENT")
+
+	case tok.is("NOT"):
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		def.kind = NOT_NULL
+		conflict, err := p.conflictClause()
+		if err != nil {
+			return nil, err
+		}
+		def.conflict = conflict
+
+	case tok.is("UNIQUE"):
+		def.kind = UNIQUE
+		conflict, err := p.conflictClause()
+		if err != nil {
+			return nil, err
+		}
+		def.conflict = conflict
+
+	case tok.is("CHECK"):
+		def.kind = CHECK
+		expression, err := p.parenthesized("CHECK")
+		if err != nil {
+			return nil, err
+		}
+		def.expression = expression
+
+	case tok.is("DEFAULT"):
+		def.kind = DEFAULT
+		value, err := p.defaultValue()
+		if err != nil {
+			return nil, err
+		}
+		def.expression = value
+
+	case tok.is("COLLATE"):
+		def.kind = COLLATE
+		name, err := p.name("collation name")
+		if err != nil {
+			return nil, err
+		}
+		def.collation = name.text
+
+	case tok.is("REFERENCES"):
+		def.kind = REFERENCES
+		ref, err := p.foreignKeyClause()
+		if err != nil {
+			return nil, err
+		}
+		def.reference = ref
+
+	case tok.is("GENERATED"), tok.is("AS"):
+		def.kind = GENERATED
+		if tok.is("GENERATED") {
+			if err := p.expect("ALWAYS", "AS"); err != nil {
+				return nil, err
+			}
+			def.always = true
+		}
+		expression, err := p.parenthesized("GENERATED")
+		if err != nil {
+			return nil, err
+		}
+		def.expression = expression
+		if p.accept("STORED") {
+			def.storage = generated.STORED
+		} else if p.accept("VIRTUAL") {
+			def.storage = generated.VIRTUAL
+		}
+
+	default:
+		return nil, p.errorf(tok, "expected column constraint, found %s", tok)
+	}
+
+	return def, nil
+}
+
+// defaultValue consumes the value of a DEFAULT constraint
+func (p *parser) defaultValue() (string, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokLParen:
+		expression, err := p.parenthesized("DEFAULT")
+		if err != nil {
+			return "", err
+		}
+		return "(" + expression + ")", nil
+
+	case tokString, tokIdentifier, tokNumber:
+		p.pos++
+		return tok.text, nil
+
+	case tokOperator:
+		number := p.peekAt(1)
+		if (tok.text == "+" || tok.text == "-") && number.kind == tokNumber {
+			p.pos += 2
+			return tok.text + number.text, nil
+		}
+
+	case tokWord:
+		for _, literal := range []string{"NULL", "TRUE", "FALSE", "CURRENT_TIME", "CURRENT_DATE", "CURRENT_TIMESTAMP"} {
+			if tok.is(literal) {
+				p.pos++
+				return literal, nil
+			}
+		}
+	}
+	return "", p.errorf(tok, "DEFAULT expects a literal, a signed number or a parenthesized expression, found %s", tok)
+}
+
+// foreignKeyClause consumes everything after the REFERENCES keyword
+func (p *parser) foreignKeyClause() (*referenceDef, error) {
+	table, err := p.name("foreign table name")
+	if err != nil {
+		return nil, err
+	}
+	ref := &referenceDef{table: unquoteIdentifier(table.text)}
+
+	if p.peek().kind == tokLParen {
+		p.pos++
+		for {
+			col, err := p.name("foreign column name")
+			if err != nil {
+				return nil, err
+			}
+			ref.columns = append(ref.columns, unquoteIdentifier(col.text))
+
+			sep := p.next()
+			if sep.kind == tokRParen {
+				break
+			}
+			if sep.kind != tokComma {
+				return nil, p.errorf(sep, "expected , or ) in foreign column list, found %s", sep)
+			}
+		}
+	}
+
+	for {
+		switch {
+		case p.peek().is("ON") && !p.peekAt(1).is("CONFLICT"):
+			p.pos++
+			event := p.next()
+			action, err := p.rowAction()
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case event.is("DELETE"):
+				ref.onDelete = action
+			case event.is("UPDATE"):
+				ref.onUpdate = action
+			default:
+				return nil, p.errorf(event, "expected DELETE or UPDATE after ON, found %s", event)
+			}
+
+		case p.peek().is("MATCH"):
+			p.pos++
+			name, err := p.name("match name")
+			if err != nil {
+				return nil, err
+			}
+			ref.match = name.text
+
+		case p.peek().is("DEFERRABLE"):
+			p.pos++
+			initially, err := p.initially()
+			if err != nil {
+				return nil, err
+			}
+			ref.deferrable = &initially
+
+		case p.peek().is("NOT") && p.peekAt(1).is("DEFERRABLE"):
+			p.pos += 2
+			initially, err := p.initially()
+			if err != nil {
+				return nil, err
+			}
+			ref.notDeferrable = &initially
+
+		default:
+			return ref, nil
+		}
+	}
+}
+
+func (p *parser) rowAction() (string, error) {
+	tok := p.next()
+	switch {
+	case tok.is("CASCADE"), tok.is("RESTRICT"):
+		return strings.ToUpper(tok.text), nil
+	case tok.is("SET") && p.accept("NULL"):
+		return "SET NULL", nil
+	case tok.is("SET") && p.accept("DEFAULT"):
+		return "SET DEFAULT", nil
+	case tok.is("NO") && p.accept("ACTION"):
+		return "NO ACTION", nil
+	}
+	return "", p.errorf(tok, "expected CASCADE, SET NULL, SET DEFAULT, RESTRICT or NO ACTION, found %s", tok)
+}
+
+// initially consumes an optional INITIALLY DEFERRED|IMMEDIATE
+func (p *parser) initially() (string, error) {
+	if !p.accept("INITIALLY") {
+		return "", nil
+	}
+	tok := p.next()
+	switch {
+	case tok.is("DEFERRED"):
+		return "INITIALLY DEFERRED", nil
+	case tok.is("IMMEDIATE"):
+		return "INITIALLY IMMEDIATE", nil
+	}
+	return "", p.errorf(tok, "expected DEFERRED or IMMEDIATE, found %s", tok)
+}
+
+// unquoteIdentifier strips double quote, backtick or bracket quoting from an identifier
+func unquoteIdentifier(name string) string {
+	if len(name) < 2 {
+		return name
+	}
+	switch name[0] {
+	case '"', '`':
+		quote := name[:1]
+		return strings.ReplaceAll(name[1:len(name)-1], quote+quote, quote)
+	case '[':
+		return name[1 : len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,61 @@
+package column
+
+import (
+	"errors"
+	"testing"
+
+	types "github.com/Nevoral/sqlofi/internal/sqlite/Types"
+)
+
+func TestParseColumnTag(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		want string
+	}{
+		{"quoted default with spaces", `NOT NULL DEFAULT 'hello world'`, "name TEXT NOT NULL DEFAULT 'hello world'"},
+		{"escaped quote", `DEFAULT 'O''Brien'`, "name TEXT DEFAULT 'O''Brien'"},
+		{"line comment", "NOT NULL -- required\nDEFAULT 'x'", "name TEXT NOT NULL DEFAULT 'x'"},
+		{"block comment", `NOT NULL /* required */ UNIQUE`, "name TEXT NOT NULL UNIQUE"},
+		{"lowercase keywords", `not null default 'x'`, "name TEXT NOT NULL DEFAULT 'x'"},
+		{"negative default", `DEFAULT -1`, "name TEXT DEFAULT -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := NewColumn("name", types.TEXT)
+			if err := col.parseColumnTag(tt.tag); err != nil {
+				t.Fatal(err)
+			}
+			if got := col.Build(); got != tt.want {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColumnTagErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		tag    string
+		offset int
+		msg    string
+	}{
+		{"unterminated quote", `DEFAULT 'abc`, 8, "unterminated quote '"},
+		{"unknown keyword", `NULLX`, 0, `expected column constraint, found "NULLX"`},
+		{"unbalanced check", `CHECK (price > 0`, 6, "unbalanced parentheses in CHECK"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := NewColumn("name", types.TEXT)
+			err := col.parseColumnTag(tt.tag)
+
+			var tagErr *TagError
+			if !errors.As(err, &tagErr) {
+				t.Fatalf("error = %v, want a *TagError", err)
+			}
+			if tagErr.Offset != tt.offset || tagErr.Msg != tt.msg {
+				t.Errorf("error at %d %q, want at %d %q", tagErr.Offset, tagErr.Msg, tt.offset, tt.msg)
+			}
+		})
+	}
+}
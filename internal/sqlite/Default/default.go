@@ -11,7 +11,7 @@ import (
 
 func NewDefaultValue[T expr.Expression | types.LiteralValue | types.SignedNumber](value T) string {
 	if val, ok := any(value).(expr.Expression); ok {
-		return fmt.Sprintf("(%s)", val.Build())
+		return fmt.Sprintf("(%s)", val.Inline())
 	} else if val, ok := any(value).(types.LiteralValue); ok {
 		return fmt.Sprintf("%s", val.String())
 	} else if val, ok := any(value).(types.SignedNumber); ok {
@@ -69,12 +69,14 @@ func parseDefaultValue(content string) string {
 		return NewDefaultValue(types.CURRENT_TIMESTAMP_VALUE)
 	}
 
-	// Handle string literals (enclosed in quotes)
-	if (strings.HasPrefix(content, "'") && strings.HasSuffix(content, "'")) ||
-		(strings.HasPrefix(content, "\"") && strings.HasSuffix(content, "\"")) {
-		// Remove surrounding quotes
-		value := content[1 : len(content)-1]
-		return NewDefaultValue(types.LiteralValue(value))
+	// Handle string literals (enclosed in quotes), they are kept quoted
+	// because an unquoted word would be taken for an identifier
+	if len(content) > 1 && strings.HasPrefix(content, "'") && strings.HasSuffix(content, "'") {
+		return NewDefaultValue(types.LiteralValue(content))
+	}
+	if len(content) > 1 && strings.HasPrefix(content, "\"") && strings.HasSuffix(content, "\"") {
+		value := strings.ReplaceAll(content[1:len(content)-1], `""`, `"`)
+		return NewDefaultValue(types.LiteralValue("'" + strings.ReplaceAll(value, "'", "''") + "'"))
 	}
 
 	// Handle expressions (enclosed in parentheses)
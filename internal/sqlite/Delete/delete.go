@@ -0,0 +1,129 @@
+package deletestmt
+
+import (
+	"fmt"
+
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	returning "github.com/Nevoral/sqlofi/internal/sqlite/Returning"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	with "github.com/Nevoral/sqlofi/internal/sqlite/With"
+)
+
+// NewDelete creates a DELETE from the table
+func NewDelete(table string) *Delete {
+	return &Delete{
+		table: table,
+	}
+}
+
+// Delete is a DELETE statement, it refuses to render without WHERE unless All is called
+type Delete struct {
+	with      *with.With
+	table     string
+	alias     string
+	where     *expr.Expression
+	all       bool
+	returning returning.Returning
+	orderBy   []*selectstmt.OrderBy
+	limit     int
+	offset    int
+	hasLimit  bool
+	hasOffset bool
+}
+
+// With sets the WITH clause of the DELETE statement
+func (d *Delete) With(clause *with.With) *Delete {
+	d.with = clause
+	return d
+}
+
+// Alias sets an alias for the table
+func (d *Delete) Alias(alias string) *Delete {
+	d.alias = alias
+	return d
+}
+
+// Where sets the WHERE clause
+func (d *Delete) Where(condition *expr.Expression) *Delete {
+	d.where = condition
+	return d
+}
+
+// All allows the DELETE without WHERE, it deletes every row of the table
+func (d *Delete) All() *Delete {
+	d.all = true
+	return d
+}
+
+// Returning sets the RETURNING clause
+func (d *Delete) Returning(columns ...*selectstmt.ResultColumn) *Delete {
+	d.returning = columns
+	return d
+}
+
+// OrderBy sets the ORDER BY clause, SQLite needs SQLITE_ENABLE_UPDATE_DELETE_LIMIT for it
+func (d *Delete) OrderBy(orderBy ...*selectstmt.OrderBy) *Delete {
+	d.orderBy = orderBy
+	return d
+}
+
+// Limit sets the LIMIT clause, SQLite needs SQLITE_ENABLE_UPDATE_DELETE_LIMIT for it
+func (d *Delete) Limit(limit int) *Delete {
+	d.limit = limit
+	d.hasLimit = true
+	return d
+}
+
+// Offset sets the OFFSET clause, it requires LIMIT
+func (d *Delete) Offset(offset int) *Delete {
+	d.offset = offset
+	d.hasOffset = true
+	return d
+}
+
+// Render writes the DELETE statement
+func (d *Delete) Render(w *expr.Writer) {
+	if d.where == nil && !d.all {
+		panic(fmt.Errorf("Error DELETE from %s without WHERE, call All to delete every row", d.table))
+	}
+	if (len(d.orderBy) > 0 || d.hasOffset) && !d.hasLimit {
+		panic(fmt.Errorf("Error DELETE from %s with ORDER BY or OFFSET without LIMIT", d.table))
+	}
+
+	if d.with != nil {
+		d.with.Render(w)
+	}
+
+	w.WriteString("DELETE FROM " + d.table)
+	if d.alias != "" {
+		w.WriteString(" AS " + d.alias)
+	}
+	if d.where != nil {
+		w.WriteString(" WHERE ")
+		d.where.Render(w)
+	}
+	d.returning.Render(w)
+
+	if len(d.orderBy) > 0 {
+		w.WriteString(" ORDER BY ")
+		for i, order := range d.orderBy {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			order.Render(w)
+		}
+	}
+	if d.hasLimit {
+		fmt.Fprintf(w, " LIMIT %d", d.limit)
+	}
+	if d.hasOffset {
+		fmt.Fprintf(w, " OFFSET %d", d.offset)
+	}
+}
+
+// Build returns the SQL of the DELETE statement and the bound values
+func (d *Delete) Build() (string, []any) {
+	w := expr.NewWriter(false)
+	d.Render(w)
+	return w.String(), w.Args()
+}
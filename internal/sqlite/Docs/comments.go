@@ -0,0 +1,197 @@
+package docs
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// TypeComments holds the doc comments of a struct type and its fields
+type TypeComments struct {
+	Doc    string
+	Fields map[string]string
+}
+
+// Comments maps struct types, keyed by their package path and name, to their doc comments
+type Comments map[string]*TypeComments
+
+// LoadComments parses the Go files of the directories and collects the doc
+// comments of their struct types, a field without a doc comment uses its line comment
+func (c Comments) LoadComments(dirs ...string) error {
+	for _, dir := range dirs {
+		if err := c.loadDir(dir, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDir collects the comments of the package in the directory, an empty
+// pkgPath is derived from the package name and the go.mod of the module
+func (c Comments) loadDir(dir, pkgPath string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		if pkgPath == "" {
+			pkgPath = importPath(dir, file.Name.Name)
+		}
+		c.addFile(pkgPath, file)
+	}
+	return nil
+}
+
+// importPath returns the import path of the package in the directory, reflect
+// reports the path of the main package as "main"
+func importPath(dir, pkgName string) string {
+	if pkgName == "main" {
+		return "main"
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return pkgName
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		if module := modulePath(filepath.Join(root, "go.mod")); module != "" {
+			rel, err := filepath.Rel(root, abs)
+			if err != nil {
+				return pkgName
+			}
+			return path.Join(module, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(root) == root {
+			break
+		}
+	}
+	// Outside of a module the package is found in GOPATH
+	if pkg, err := build.ImportDir(abs, build.FindOnly); err == nil && pkg.ImportPath != "." {
+		return pkg.ImportPath
+	}
+	return pkgName
+}
+
+// modulePath returns the module path declared in the go.mod file, "" when there is none
+func modulePath(goMod string) string {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+func (c Comments) addFile(pkgPath string, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			comments := &TypeComments{
+				Doc:    strings.TrimSpace(doc.Text()),
+				Fields: map[string]string{},
+			}
+			for _, field := range structType.Fields.List {
+				text := field.Doc.Text()
+				if text == "" {
+					text = field.Comment.Text()
+				}
+				for _, name := range field.Names {
+					comments.Fields[name.Name] = strings.TrimSpace(text)
+				}
+			}
+			c[pkgPath+"."+typeSpec.Name.Name] = comments
+		}
+	}
+}
+
+// LoadModelComments locates the source directory of the model package and loads its comments,
+// models of the main package are looked up in the working directory
+func (c Comments) LoadModelComments(model any) error {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	if _, ok := c[typeKey(t)]; ok {
+		return nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if t.PkgPath() == "main" {
+		return c.loadDir(wd, "main")
+	}
+	pkg, err := build.Import(t.PkgPath(), wd, build.FindOnly)
+	if err != nil {
+		// Without the sources the docs are generated without the comments
+		return nil
+	}
+	return c.loadDir(pkg.Dir, t.PkgPath())
+}
+
+// Field returns the comment of the field of the model, "" when there is none
+func (c Comments) Field(model any, field string) string {
+	if comments := c.of(model); comments != nil {
+		return comments.Fields[field]
+	}
+	return ""
+}
+
+// Type returns the doc comment of the model, "" when there is none
+func (c Comments) Type(model any) string {
+	if comments := c.of(model); comments != nil {
+		return comments.Doc
+	}
+	return ""
+}
+
+func (c Comments) of(model any) *TypeComments {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return nil
+	}
+	return c[typeKey(t)]
+}
+
+// typeKey is the key of the type in Comments
+func typeKey(t reflect.Type) string {
+	return t.PkgPath() + "." + t.Name()
+}
@@ -0,0 +1,28 @@
+package docs
+
+import "testing"
+
+func TestCommentsKeyedByPackagePath(t *testing.T) {
+	comments := Comments{}
+	if err := comments.LoadComments("."); err != nil {
+		t.Fatal(err)
+	}
+	const key = "github.com/Nevoral/sqlofi/internal/sqlite/Docs.TypeComments"
+	if comments[key] == nil {
+		t.Fatalf("no comments under %s", key)
+	}
+
+	// A type of another package with the same name has comments of its own
+	comments["other/pkg.TypeComments"] = &TypeComments{Doc: "other"}
+	if got := comments.Type(TypeComments{}); got != comments[key].Doc {
+		t.Errorf("Type() = %q, want %q", got, comments[key].Doc)
+	}
+
+	loaded := Comments{}
+	if err := loaded.LoadModelComments(TypeComments{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Type(TypeComments{}); got == "" || got != comments[key].Doc {
+		t.Errorf("Type() = %q after LoadModelComments, want %q", got, comments[key].Doc)
+	}
+}
@@ -0,0 +1,270 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"slices"
+	"strings"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
+	index "github.com/Nevoral/sqlofi/internal/sqlite/Index"
+	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
+	trigger "github.com/Nevoral/sqlofi/internal/sqlite/Trigger"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// Format is the output language of the documentation
+type Format string
+
+const (
+	MARKDOWN Format = "markdown"
+	HTML     Format = "html"
+)
+
+// Document is the reference documentation of a schema
+type Document struct {
+	Title  string
+	Tables []*TableDoc
+}
+
+// TableDoc documents a single table
+type TableDoc struct {
+	Name        string
+	Description string
+	Columns     []*ColumnDoc
+	Keys        []string
+	ForeignKeys []string
+	Checks      []string
+	Indexes     []string
+	Triggers    []string
+}
+
+// ColumnDoc documents a single column
+type ColumnDoc struct {
+	Name        string
+	Type        string
+	Constraints string
+	Default     string
+	Generated   string
+	Description string
+}
+
+// NewDocument collects the documentation of the tables together with their indexes and triggers
+func NewDocument(title string, tables []*table.Table, enums enum.Registry, indexes []*index.Index, triggers []*trigger.Trigger, comments Comments) *Document {
+	doc := &Document{Title: title}
+	for _, t := range tables {
+		tableDoc := &TableDoc{
+			Name:        t.TableName(),
+			Description: comments.Type(t.Model()),
+		}
+
+		// Columns of the model map back to the struct fields holding their comments
+		fields := map[string]string{}
+		for _, field := range reflectutil.GetStructFields(t.Model()) {
+			fields[utils.ToSnakeCase(field.Name)] = field.Name
+		}
+
+		for _, col := range t.EnumColumns(enums) {
+			tableDoc.Columns = append(tableDoc.Columns, columnDoc(tableDoc, col, comments.Field(t.Model(), fields[col.Name()])))
+		}
+
+		for _, constraint := range t.Constraints() {
+			switch {
+			case constraint.PrimaryKey != nil, constraint.Unique != nil:
+				tableDoc.Keys = append(tableDoc.Keys, constraint.Build())
+			case constraint.Check != nil:
+				tableDoc.Checks = append(tableDoc.Checks, constraint.Build())
+			case constraint.ForeignKey != nil:
+				tableDoc.ForeignKeys = append(tableDoc.ForeignKeys, constraint.Build())
+			}
+		}
+
+		for _, idx := range slices.Concat(indexes, t.Indexes()) {
+			if tableName(idx.Table()) == t.TableName() {
+				tableDoc.Indexes = append(tableDoc.Indexes, idx.Build())
+			}
+		}
+		for _, trig := range triggers {
+			if tableName(trig.Table()) == t.TableName() {
+				tableDoc.Triggers = append(tableDoc.Triggers, trig.Build())
+			}
+		}
+		doc.Tables = append(doc.Tables, tableDoc)
+	}
+	return doc
+}
+
+func columnDoc(tableDoc *TableDoc, col *column.Column, description string) *ColumnDoc {
+	var (
+		colDoc = &ColumnDoc{
+			Name:        col.Name(),
+			Type:        col.Type().String(),
+			Description: description,
+		}
+		constraints []string
+	)
+
+	for _, constraint := range col.Constraints() {
+		switch constraint.Kind {
+		case column.DEFAULT:
+			colDoc.Default = constraint.Expression
+		case column.GENERATED:
+			colDoc.Generated = strings.TrimSpace(constraint.Expression + " " + constraint.Storage.String())
+		case column.CHECK:
+			tableDoc.Checks = append(tableDoc.Checks, fmt.Sprintf("%s: %s", col.Name(), constraint.Build()))
+		case column.REFERENCES:
+			tableDoc.ForeignKeys = append(tableDoc.ForeignKeys, fmt.Sprintf("%s %s", col.Name(), constraint.Build()))
+		default:
+			constraints = append(constraints, constraint.Build())
+		}
+	}
+	colDoc.Constraints = strings.Join(constraints, " ")
+	return colDoc
+}
+
+// tableName returns the SQL name of a model, table or table name
+func tableName(table any) string {
+	if name, ok := table.(string); ok {
+		return name
+	}
+	return reflectutil.GetTableName(table)
+}
+
+// Build renders the document in the given format
+func (d *Document) Build(format Format) string {
+	switch format {
+	case MARKDOWN:
+		return d.markdown()
+	case HTML:
+		var buf bytes.Buffer
+		if err := htmlTemplate.Execute(&buf, d); err != nil {
+			panic(err)
+		}
+		return buf.String()
+	default:
+		panic(fmt.Errorf("unknown documentation format '%s'", format))
+	}
+}
+
+func (d *Document) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Title)
+	for _, t := range d.Tables {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", t.Name, strings.ToLower(t.Name))
+	}
+
+	for _, t := range d.Tables {
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Name)
+		if t.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", t.Description)
+		}
+
+		b.WriteString("| Column | Type | Constraints | Default | Generated | Description |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, col := range t.Columns {
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s |\n",
+				col.Name, cell(col.Type), code(col.Constraints), code(col.Default), code(col.Generated), cell(col.Description))
+		}
+
+		markdownList(&b, "Keys", t.Keys)
+		markdownList(&b, "Foreign keys", t.ForeignKeys)
+		markdownList(&b, "Checks", t.Checks)
+		markdownSQL(&b, "Indexes", t.Indexes)
+		markdownSQL(&b, "Triggers", t.Triggers)
+	}
+	return b.String()
+}
+
+func markdownList(b *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "- `%s`\n", item)
+	}
+}
+
+func markdownSQL(b *strings.Builder, title string, statements []string) {
+	if len(statements) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n```sql\n", title)
+	for _, statement := range statements {
+		fmt.Fprintf(b, "%s;\n", statement)
+	}
+	b.WriteString("```\n")
+}
+
+// cell escapes text for a Markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func code(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + cell(text) + "`"
+}
+
+var htmlTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"list": func(title string, items []string) []any { return []any{title, items} },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #eee; }
+pre { background: #f6f6f6; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+{{- range .Tables}}
+<li><a href="#{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- range .Tables}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+<table>
+<tr><th>Column</th><th>Type</th><th>Constraints</th><th>Default</th><th>Generated</th><th>Description</th></tr>
+{{- range .Columns}}
+<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{with .Constraints}}<code>{{.}}</code>{{end}}</td><td>{{with .Default}}<code>{{.}}</code>{{end}}</td><td>{{with .Generated}}<code>{{.}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{- end}}
+</table>
+{{- template "list" (list "Keys" .Keys)}}
+{{- template "list" (list "Foreign keys" .ForeignKeys)}}
+{{- template "list" (list "Checks" .Checks)}}
+{{- template "sql" (list "Indexes" .Indexes)}}
+{{- template "sql" (list "Triggers" .Triggers)}}
+{{- end}}
+</body>
+</html>
+{{define "list"}}{{with index . 1}}
+<h3>{{index $ 0}}</h3>
+<ul>
+{{- range .}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+{{- end}}{{end}}
+{{- define "sql"}}{{with index . 1}}
+<h3>{{index $ 0}}</h3>
+<pre><code>
+{{- range .}}{{.}};
+{{end}}</code></pre>
+{{- end}}{{end}}
+`))
@@ -0,0 +1,296 @@
+package erdiagram
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	foreignkey "github.com/Nevoral/sqlofi/internal/sqlite/ForeignKey"
+	idxcol "github.com/Nevoral/sqlofi/internal/sqlite/IndexedColumn"
+	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// Format is the output language of the diagram
+type Format string
+
+const (
+	MERMAID  Format = "mermaid"
+	GRAPHVIZ Format = "dot"
+)
+
+// Cardinality is the number of rows on one side of a relationship
+type Cardinality int
+
+const (
+	ZERO_OR_ONE Cardinality = iota
+	EXACTLY_ONE
+	ZERO_OR_MANY
+)
+
+// Entity is a table of the diagram
+type Entity struct {
+	Name       string
+	Attributes []*Attribute
+}
+
+// Attribute is a column of an entity
+type Attribute struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+	ForeignKey bool
+	Unique     bool
+	NotNull    bool
+}
+
+// Relationship is a foreign key between two entities
+type Relationship struct {
+	Child         string
+	ChildColumns  []string
+	Parent        string
+	ParentColumns []string
+	OnDelete      string
+	ChildSide     Cardinality
+	ParentSide    Cardinality
+}
+
+// Diagram is the entity-relationship model of a set of tables
+type Diagram struct {
+	Entities      []*Entity
+	Relationships []*Relationship
+}
+
+// NewDiagram collects the entities and relationships of the tables
+func NewDiagram(tables []*table.Table) *Diagram {
+	d := &Diagram{}
+	for _, t := range tables {
+		d.addTable(t)
+	}
+	return d
+}
+
+func (d *Diagram) addTable(t *table.Table) {
+	var (
+		columns    = t.Columns()
+		entity     = &Entity{Name: t.TableName()}
+		primaryKey []string
+		uniques    [][]string
+		references []*foreignkey.References
+	)
+
+	for _, constraint := range t.Constraints() {
+		switch {
+		case constraint.PrimaryKey != nil:
+			primaryKey = indexedNames(constraint.PrimaryKey.Columns())
+		case constraint.Unique != nil:
+			uniques = append(uniques, indexedNames(constraint.Unique.Columns()))
+		case constraint.ForeignKey != nil:
+			references = append(references, constraint.ForeignKey)
+		}
+	}
+	if len(primaryKey) > 0 {
+		uniques = append(uniques, primaryKey)
+	}
+
+	for _, col := range columns {
+		attr := &Attribute{
+			Name:       col.Name(),
+			Type:       col.Type().String(),
+			PrimaryKey: col.IsPrimaryKey() || slices.Contains(primaryKey, col.Name()),
+			Unique:     col.IsUnique(),
+			NotNull:    col.IsNotNull(),
+		}
+		if attr.PrimaryKey || attr.Unique {
+			uniques = append(uniques, []string{col.Name()})
+		}
+		for _, constraint := range col.Constraints() {
+			if constraint.Kind == column.REFERENCES {
+				attr.ForeignKey = true
+				references = append(references, constraint.Reference)
+			}
+		}
+		entity.Attributes = append(entity.Attributes, attr)
+	}
+
+	for _, ref := range references {
+		childColumns := make([]string, len(ref.GetColumns()))
+		for i, col := range ref.GetColumns() {
+			childColumns[i] = utils.ToSnakeCase(col)
+			for _, attr := range entity.Attributes {
+				if attr.Name == childColumns[i] {
+					attr.ForeignKey = true
+				}
+			}
+		}
+
+		rel := &Relationship{
+			Child:         entity.Name,
+			ChildColumns:  childColumns,
+			Parent:        ref.ForeignTableName(),
+			ParentColumns: ref.ForeignColumns(),
+			OnDelete:      ref.OnDeleteAction(),
+			ChildSide:     ZERO_OR_MANY,
+			ParentSide:    EXACTLY_ONE,
+		}
+
+		// A nullable foreign key may point nowhere
+		for _, name := range childColumns {
+			idx := slices.IndexFunc(entity.Attributes, func(attr *Attribute) bool { return attr.Name == name })
+			if idx == -1 || !entity.Attributes[idx].NotNull {
+				rel.ParentSide = ZERO_OR_ONE
+			}
+		}
+
+		// A unique foreign key makes the relationship one-to-one
+		for _, key := range uniques {
+			if sameColumns(key, childColumns) {
+				rel.ChildSide = ZERO_OR_ONE
+			}
+		}
+		d.Relationships = append(d.Relationships, rel)
+	}
+	d.Entities = append(d.Entities, entity)
+}
+
+func indexedNames(columns []*idxcol.IndexedColumn) []string {
+	var names []string
+	for _, col := range columns {
+		if col.ColumnName() != "" {
+			names = append(names, col.ColumnName())
+		}
+	}
+	return names
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, col := range a {
+		if !slices.Contains(b, col) {
+			return false
+		}
+	}
+	return true
+}
+
+// Build renders the diagram in the given format
+func (d *Diagram) Build(format Format) string {
+	switch format {
+	case MERMAID:
+		return d.mermaid()
+	case GRAPHVIZ:
+		return d.dot()
+	default:
+		panic(fmt.Errorf("unknown diagram format '%s'", format))
+	}
+}
+
+func (d *Diagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+
+	for _, rel := range d.Relationships {
+		fmt.Fprintf(&b, "    %s %s--%s %s : %q\n",
+			rel.Parent, mermaidLeft[rel.ParentSide], mermaidRight[rel.ChildSide], rel.Child, rel.label())
+	}
+
+	for _, entity := range d.Entities {
+		fmt.Fprintf(&b, "    %s {\n", entity.Name)
+		for _, attr := range entity.Attributes {
+			fmt.Fprintf(&b, "        %s %s", strings.ReplaceAll(attr.Type, " ", "_"), attr.Name)
+			if keys := attr.keys(); len(keys) > 0 {
+				fmt.Fprintf(&b, " %s", strings.Join(keys, ", "))
+			}
+			if attr.NotNull && !attr.PrimaryKey {
+				b.WriteString(` "NOT NULL"`)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	return b.String()
+}
+
+// mermaidLeft and mermaidRight are the crow's foot markers on each side of "--"
+var (
+	mermaidLeft = map[Cardinality]string{
+		ZERO_OR_ONE:  "|o",
+		EXACTLY_ONE:  "||",
+		ZERO_OR_MANY: "}o",
+	}
+	mermaidRight = map[Cardinality]string{
+		ZERO_OR_ONE:  "o|",
+		EXACTLY_ONE:  "||",
+		ZERO_OR_MANY: "o{",
+	}
+)
+
+func (d *Diagram) dot() string {
+	var b strings.Builder
+	b.WriteString("digraph schema {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=plaintext];\n")
+
+	for _, entity := range d.Entities {
+		fmt.Fprintf(&b, "\t%q [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">\n", entity.Name)
+		fmt.Fprintf(&b, "\t\t<TR><TD BGCOLOR=\"lightgrey\" COLSPAN=\"3\"><B>%s</B></TD></TR>\n", html.EscapeString(entity.Name))
+		for _, attr := range entity.Attributes {
+			markers := attr.keys()
+			if attr.NotNull && !attr.PrimaryKey {
+				markers = append(markers, "NOT NULL")
+			}
+			fmt.Fprintf(&b, "\t\t<TR><TD PORT=%q ALIGN=\"LEFT\">%s</TD><TD ALIGN=\"LEFT\">%s</TD><TD>%s</TD></TR>\n",
+				attr.Name, html.EscapeString(attr.Name), html.EscapeString(attr.Type), strings.Join(markers, ", "))
+		}
+		b.WriteString("\t</TABLE>>];\n")
+	}
+
+	for _, rel := range d.Relationships {
+		child := fmt.Sprintf("%q", rel.Child)
+		if len(rel.ChildColumns) > 0 {
+			child += fmt.Sprintf(":%q", rel.ChildColumns[0])
+		}
+		parent := fmt.Sprintf("%q", rel.Parent)
+		if len(rel.ParentColumns) > 0 {
+			parent += fmt.Sprintf(":%q", rel.ParentColumns[0])
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [dir=both, arrowtail=%s, arrowhead=%s, label=%q];\n",
+			child, parent, dotArrows[rel.ChildSide], dotArrows[rel.ParentSide], rel.label())
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotArrows are the crow's foot arrow shapes of Graphviz
+var dotArrows = map[Cardinality]string{
+	ZERO_OR_ONE:  "teeodot",
+	EXACTLY_ONE:  "teetee",
+	ZERO_OR_MANY: "crowodot",
+}
+
+func (r *Relationship) label() string {
+	label := strings.Join(r.ChildColumns, ", ")
+	if r.OnDelete != "" {
+		label += " ON DELETE " + r.OnDelete
+	}
+	return label
+}
+
+func (a *Attribute) keys() []string {
+	var keys []string
+	if a.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if a.ForeignKey {
+		keys = append(keys, "FK")
+	}
+	if a.Unique {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
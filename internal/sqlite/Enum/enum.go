@@ -0,0 +1,85 @@
+package enum
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Registry maps a Go type to the SQL literals allowed for columns of that type
+type Registry map[reflect.Type][]string
+
+// Register stores the allowed values for the type t
+func (r Registry) Register(t reflect.Type, values []string) {
+	r[t] = values
+}
+
+// Values returns the SQL literals allowed for columns of type t.
+// Types registered in the registry take precedence over an Enum() method
+// declared on the type itself.
+func Values(t reflect.Type, registry Registry) ([]string, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	// Nullable enum columns are declared as pointers to the enum type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if values, ok := registry[t]; ok {
+		return values, true
+	}
+
+	method := reflect.New(t).MethodByName("Enum")
+	if !method.IsValid() {
+		return nil, false
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() != 1 || methodType.Out(0) != reflect.SliceOf(t) {
+		return nil, false
+	}
+
+	return Literals(method.Call(nil)[0]), true
+}
+
+// Literals converts a slice of enum values into SQL literals
+func Literals(values reflect.Value) []string {
+	literals := make([]string, 0, values.Len())
+	for i := range values.Len() {
+		literals = append(literals, Literal(values.Index(i)))
+	}
+	return literals
+}
+
+// Literal converts a single enum value into a SQL literal
+func Literal(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return quote(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	case reflect.Bool:
+		if value.Bool() {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return quote(fmt.Sprint(value.Interface()))
+	}
+}
+
+// NewEnumCheck returns the CHECK expression body limiting a column to values
+func NewEnumCheck(columnName string, values []string) string {
+	return fmt.Sprintf("%s IN (%s)", columnName, strings.Join(values, ", "))
+}
+
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -1,17 +1,174 @@
 package expr
 
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+)
+
+// Precedence is the binding strength of an operator, higher binds tighter.
+// The levels follow the SQLite operator precedence.
+type Precedence int
+
+const (
+	PREC_LOWEST     Precedence = iota // raw SQL that may contain any operator
+	PREC_OR                           // OR
+	PREC_AND                          // AND
+	PREC_NOT                          // NOT x
+	PREC_EQUALITY                     // = <> IS IN LIKE GLOB MATCH REGEXP BETWEEN ISNULL NOTNULL
+	PREC_COMPARISON                   // < <= > >=
+	PREC_BITWISE                      // & | << >>
+	PREC_ADDITIVE                     // + -
+	PREC_MULTIPLY                     // * / %
+	PREC_CONCAT                       // || -> ->>
+	PREC_UNARY                        // -x +x ~x
+	PREC_COLLATE                      // x COLLATE name
+	PREC_ATOM                         // literals, columns, function calls, CASE, CAST, subqueries
+)
+
+// Node is a node of the expression tree
+type Node interface {
+	// Precedence returns the binding strength of the node, operands binding
+	// weaker than their operator are parenthesized
+	Precedence() Precedence
+	// Render writes the SQL of the node
+	Render(w *Writer)
+}
+
+// Writer renders expression trees and the statements containing them, bound values
+// are written as parameters and collected as arguments unless the writer is inline
+type Writer struct {
+	strings.Builder
+	inline bool
+	args   []any
+}
+
+// NewWriter creates a writer, an inline writer writes bound values as escaped literals,
+// e.g. for CREATE statements that can't have parameters
+func NewWriter(inline bool) *Writer {
+	return &Writer{inline: inline}
+}
+
+// Bind writes a bound value, param is the parameter such as "?" or ":name"
+func (w *Writer) Bind(param string, value any) {
+	if w.inline {
+		(&Literal{Value: value}).Render(w)
+		return
+	}
+	w.WriteString(param)
+	if param != "" && param[0] != '?' {
+		value = sql.Named(param[1:], value)
+	}
+	w.args = append(w.args, value)
+}
+
+// Args returns the values bound by the rendered nodes
+func (w *Writer) Args() []any {
+	return w.args
+}
+
+// Build renders the node with parameters and returns the SQL and its arguments
+func Build(node Node) (string, []any) {
+	w := NewWriter(false)
+	node.Render(w)
+	return w.String(), w.Args()
+}
+
+// Inline renders the node with the bound values written as literals
+func Inline(node Node) string {
+	w := NewWriter(true)
+	node.Render(w)
+	return w.String()
+}
+
+// Node writes a node without parentheses
+func (w *Writer) Node(node Node) {
+	node.Render(w)
+}
+
+// Operand writes the operand of an operator binding with prec, it's parenthesized
+// when it binds weaker, or as strong when strict is set, e.g. for the right operand
+// of a left associative operator
+func (w *Writer) Operand(node Node, prec Precedence, strict bool) {
+	p := node.Precedence()
+	if p < prec || (strict && p == prec) {
+		w.WriteByte('(')
+		node.Render(w)
+		w.WriteByte(')')
+		return
+	}
+	node.Render(w)
+}
+
+// List writes the nodes separated by commas
+func (w *Writer) List(nodes []Node) {
+	for i, node := range nodes {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		node.Render(w)
+	}
+}
+
+// NewExpression creates an expression from raw SQL, it's rendered as is
 func NewExpression(expression string) *Expression {
 	return &Expression{
-		expression: expression,
+		node: &Raw{SQL: expression},
+	}
+}
+
+// NewNodeExpression wraps a node of the expression tree
+func NewNodeExpression(node Node) *Expression {
+	return &Expression{
+		node: node,
 	}
 }
 
 // Expression represents an SQL expression
 type Expression struct {
-	expression string
+	node Node
+}
+
+// Node returns the root of the expression tree
+func (e *Expression) Node() Node {
+	return e.node
+}
+
+// Precedence returns the precedence of the root node, so expressions nest as nodes
+func (e *Expression) Precedence() Precedence {
+	return e.node.Precedence()
+}
+
+// Render writes the SQL of the expression
+func (e *Expression) Render(w *Writer) {
+	e.node.Render(w)
+}
+
+// Build returns the SQL of the expression with parameters and their arguments
+func (e *Expression) Build() (string, []any) {
+	return Build(e.node)
+}
+
+// Inline returns the SQL of the expression with the bound values written as literals
+func (e *Expression) Inline() string {
+	return Inline(e.node)
+}
+
+// simpleRaw matches raw SQL that is a single identifier, number, string or parameter
+var simpleRaw = regexp.MustCompile(`^(?:[A-Za-z_][\w.]*|\d+(?:\.\d+)?|'(?:[^']|'')*'|\?\d*|[:@$][A-Za-z_]\w*)$`)
+
+// Raw is raw SQL, as an operand it's parenthesized unless it's a single token
+type Raw struct {
+	SQL string
+}
+
+func (r *Raw) Precedence() Precedence {
+	if simpleRaw.MatchString(strings.TrimSpace(r.SQL)) {
+		return PREC_ATOM
+	}
+	return PREC_LOWEST
 }
 
-// Build returns the string representation of the expression
-func (e *Expression) Build() string {
-	return e.expression
+func (r *Raw) Render(w *Writer) {
+	w.WriteString(r.SQL)
 }
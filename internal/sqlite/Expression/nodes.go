@@ -0,0 +1,420 @@
+package expr
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statement is a statement nested in an expression, e.g. the SELECT of a subquery
+type Statement interface {
+	Render(w *Writer)
+}
+
+// Literal is a value written into the SQL, strings are quoted and []byte is a blob
+type Literal struct {
+	Value any
+}
+
+func (l *Literal) Precedence() Precedence {
+	return valuePrecedence(l.Value)
+}
+
+// valuePrecedence returns the precedence of a written value, negative numbers are unary minus
+func valuePrecedence(value any) Precedence {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64:
+		if strings.HasPrefix(fmt.Sprint(v), "-") {
+			return PREC_UNARY
+		}
+	case float32, float64:
+		if strings.HasPrefix(fmt.Sprint(v), "-") {
+			return PREC_UNARY
+		}
+	}
+	return PREC_ATOM
+}
+
+func (l *Literal) Render(w *Writer) {
+	switch v := l.Value.(type) {
+	case nil:
+		w.WriteString("NULL")
+	case string:
+		w.WriteString(QuoteString(v))
+	case []byte:
+		w.WriteString("X'" + strings.ToUpper(hex.EncodeToString(v)) + "'")
+	case bool:
+		if v {
+			w.WriteString("TRUE")
+		} else {
+			w.WriteString("FALSE")
+		}
+	case float32:
+		w.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		fmt.Fprintf(w, "%d", v)
+	case time.Time:
+		// the layout the sqlite3 drivers write times with
+		w.WriteString(QuoteString(v.Format("2006-01-02 15:04:05.999999999-07:00")))
+	default:
+		w.WriteString(QuoteString(fmt.Sprint(v)))
+	}
+}
+
+// Param is a value bound to a parameter, "?" or a named one such as ":name".
+// An inline writer writes it as a literal.
+type Param struct {
+	Param string
+	Value any
+}
+
+func (p *Param) Precedence() Precedence {
+	return valuePrecedence(p.Value)
+}
+
+func (p *Param) Render(w *Writer) {
+	w.Bind(p.Param, p.Value)
+}
+
+// QuoteString returns the SQL string literal of s
+func QuoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// Column is a column reference, [schema.][table.]column
+type Column struct {
+	Schema string
+	Table  string
+	Name   string
+}
+
+func (c *Column) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (c *Column) Render(w *Writer) {
+	if c.Table != "" {
+		if c.Schema != "" {
+			w.WriteString(c.Schema + ".")
+		}
+		w.WriteString(c.Table + ".")
+	}
+	w.WriteString(c.Name)
+}
+
+// Unary is a prefix operator such as NOT, - and ~, or a postfix one such as ISNULL
+type Unary struct {
+	Operator string
+	Operand  Node
+	Postfix  bool
+	Prec     Precedence
+}
+
+func (u *Unary) Precedence() Precedence {
+	return u.Prec
+}
+
+func (u *Unary) Render(w *Writer) {
+	if u.Postfix {
+		w.Operand(u.Operand, u.Prec, false)
+		w.WriteString(" " + u.Operator)
+		return
+	}
+
+	w.WriteString(u.Operator)
+	if strings.ToUpper(u.Operator) == "NOT" {
+		w.WriteByte(' ')
+	}
+	// parenthesizing an operand of the same level avoids "- -x" and "NOT NOT x" surprises
+	w.Operand(u.Operand, u.Prec, true)
+}
+
+// Binary is a left associative operator between two operands
+type Binary struct {
+	Operator string
+	Left     Node
+	Right    Node
+	Prec     Precedence
+}
+
+func (b *Binary) Precedence() Precedence {
+	return b.Prec
+}
+
+func (b *Binary) Render(w *Writer) {
+	w.Operand(b.Left, b.Prec, false)
+	w.WriteString(" " + b.Operator + " ")
+	w.Operand(b.Right, b.Prec, true)
+}
+
+// Chain joins its operands with an associative operator, e.g. a AND b AND c
+type Chain struct {
+	Operator string
+	Operands []Node
+	Prec     Precedence
+}
+
+func (c *Chain) Precedence() Precedence {
+	if len(c.Operands) == 1 {
+		return c.Operands[0].Precedence()
+	}
+	return c.Prec
+}
+
+func (c *Chain) Render(w *Writer) {
+	if len(c.Operands) == 1 {
+		w.Node(c.Operands[0])
+		return
+	}
+	for i, operand := range c.Operands {
+		if i > 0 {
+			w.WriteString(" " + c.Operator + " ")
+		}
+		w.Operand(operand, c.Prec, false)
+	}
+}
+
+// Pattern is a LIKE, GLOB, REGEXP or MATCH operator with an optional ESCAPE for LIKE
+type Pattern struct {
+	Operator string
+	Not      bool
+	Left     Node
+	Right    Node
+	Escape   Node
+}
+
+func (p *Pattern) Precedence() Precedence {
+	return PREC_EQUALITY
+}
+
+func (p *Pattern) Render(w *Writer) {
+	w.Operand(p.Left, PREC_EQUALITY, false)
+	if p.Not {
+		w.WriteString(" NOT")
+	}
+	w.WriteString(" " + p.Operator + " ")
+	w.Operand(p.Right, PREC_EQUALITY, true)
+	if p.Escape != nil {
+		w.WriteString(" ESCAPE ")
+		w.Operand(p.Escape, PREC_EQUALITY, true)
+	}
+}
+
+// Between is x [NOT] BETWEEN low AND high
+type Between struct {
+	Not     bool
+	Operand Node
+	Low     Node
+	High    Node
+}
+
+func (b *Between) Precedence() Precedence {
+	return PREC_EQUALITY
+}
+
+func (b *Between) Render(w *Writer) {
+	w.Operand(b.Operand, PREC_EQUALITY, false)
+	if b.Not {
+		w.WriteString(" NOT")
+	}
+	w.WriteString(" BETWEEN ")
+	w.Operand(b.Low, PREC_EQUALITY, true)
+	w.WriteString(" AND ")
+	w.Operand(b.High, PREC_EQUALITY, true)
+}
+
+// In is x [NOT] IN (values) or x [NOT] IN (subquery)
+type In struct {
+	Not      bool
+	Operand  Node
+	Values   []Node
+	Subquery Statement
+}
+
+func (i *In) Precedence() Precedence {
+	return PREC_EQUALITY
+}
+
+func (i *In) Render(w *Writer) {
+	w.Operand(i.Operand, PREC_EQUALITY, false)
+	if i.Not {
+		w.WriteString(" NOT")
+	}
+	w.WriteString(" IN (")
+	if i.Subquery != nil {
+		i.Subquery.Render(w)
+	} else {
+		w.List(i.Values)
+	}
+	w.WriteByte(')')
+}
+
+// Function is a function call, name([DISTINCT] args) or name(*),
+// aggregate and window functions may have a FILTER and an OVER clause
+type Function struct {
+	Name     string
+	Distinct bool
+	Star     bool
+	Args     []Node
+	Filter   Node
+	Over     *Over
+}
+
+func (f *Function) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (f *Function) Render(w *Writer) {
+	w.WriteString(f.Name + "(")
+	if f.Star {
+		w.WriteByte('*')
+	} else {
+		if f.Distinct {
+			w.WriteString("DISTINCT ")
+		}
+		w.List(f.Args)
+	}
+	w.WriteByte(')')
+	if f.Filter != nil {
+		w.WriteString(" FILTER (WHERE ")
+		w.Node(f.Filter)
+		w.WriteByte(')')
+	}
+	if f.Over != nil {
+		f.Over.render(w)
+	}
+}
+
+// Case is CASE [operand] WHEN ... THEN ... [ELSE ...] END
+type Case struct {
+	Operand Node
+	When    []Node
+	Then    []Node
+	Else    Node
+}
+
+func (c *Case) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (c *Case) Render(w *Writer) {
+	w.WriteString("CASE")
+	if c.Operand != nil {
+		w.WriteByte(' ')
+		w.Node(c.Operand)
+	}
+	for i := range c.When {
+		w.WriteString(" WHEN ")
+		w.Node(c.When[i])
+		w.WriteString(" THEN ")
+		w.Node(c.Then[i])
+	}
+	if c.Else != nil {
+		w.WriteString(" ELSE ")
+		w.Node(c.Else)
+	}
+	w.WriteString(" END")
+}
+
+// Cast is CAST(x AS type)
+type Cast struct {
+	Operand Node
+	Type    string
+}
+
+func (c *Cast) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (c *Cast) Render(w *Writer) {
+	w.WriteString("CAST(")
+	w.Node(c.Operand)
+	w.WriteString(" AS " + c.Type + ")")
+}
+
+// Collate is x COLLATE name
+type Collate struct {
+	Operand   Node
+	Collation string
+}
+
+func (c *Collate) Precedence() Precedence {
+	return PREC_COLLATE
+}
+
+func (c *Collate) Render(w *Writer) {
+	w.Operand(c.Operand, PREC_COLLATE, false)
+	w.WriteString(" COLLATE " + c.Collation)
+}
+
+// Exists is [NOT] EXISTS (subquery)
+type Exists struct {
+	Not      bool
+	Subquery Statement
+}
+
+func (e *Exists) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (e *Exists) Render(w *Writer) {
+	if e.Not {
+		w.WriteString("NOT ")
+	}
+	w.WriteString("EXISTS (")
+	e.Subquery.Render(w)
+	w.WriteByte(')')
+}
+
+// Subquery is a parenthesized SELECT used as a value
+type Subquery struct {
+	Statement Statement
+}
+
+func (s *Subquery) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (s *Subquery) Render(w *Writer) {
+	w.WriteByte('(')
+	s.Statement.Render(w)
+	w.WriteByte(')')
+}
+
+// Row is a parenthesized list of values, (a, b, ...)
+type Row struct {
+	Values []Node
+}
+
+func (r *Row) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (r *Row) Render(w *Writer) {
+	w.WriteByte('(')
+	w.List(r.Values)
+	w.WriteByte(')')
+}
+
+// Raise is the RAISE function of triggers
+type Raise struct {
+	Action  string
+	Message Node
+}
+
+func (r *Raise) Precedence() Precedence {
+	return PREC_ATOM
+}
+
+func (r *Raise) Render(w *Writer) {
+	w.WriteString("RAISE(" + r.Action)
+	if r.Message != nil {
+		w.WriteString(", ")
+		w.Node(r.Message)
+	}
+	w.WriteByte(')')
+}
@@ -0,0 +1,72 @@
+package expr
+
+import "testing"
+
+func col(name string) Node {
+	return &Column{Name: name}
+}
+
+func TestRenderParentheses(t *testing.T) {
+	a, b, c := col("a"), col("b"), col("c")
+	one := &Literal{Value: 1}
+
+	tests := []struct {
+		name string
+		node Node
+		want string
+	}{
+		{
+			"NOT of AND of OR",
+			&Unary{Operator: "NOT", Prec: PREC_NOT, Operand: &Chain{Operator: "AND", Prec: PREC_AND, Operands: []Node{
+				a,
+				&Chain{Operator: "OR", Prec: PREC_OR, Operands: []Node{b, c}},
+			}}},
+			"NOT (a AND (b OR c))",
+		},
+		{
+			"right operand of the same level",
+			&Binary{Operator: "-", Prec: PREC_ADDITIVE, Left: a, Right: &Binary{Operator: "-", Prec: PREC_ADDITIVE, Left: a, Right: one}},
+			"a - (a - 1)",
+		},
+		{
+			"left operand of the same level",
+			&Binary{Operator: "-", Prec: PREC_ADDITIVE, Left: &Binary{Operator: "-", Prec: PREC_ADDITIVE, Left: a, Right: one}, Right: a},
+			"a - 1 - a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Inline(tt.node); got != tt.want {
+				t.Errorf("Inline() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderLiteral(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"O'Brien", "'O''Brien'"},
+		{[]byte{0x01, 0xff}, "X'01FF'"},
+		{true, "TRUE"},
+		{false, "FALSE"},
+		{nil, "NULL"},
+	}
+	for _, tt := range tests {
+		if got := Inline(&Literal{Value: tt.value}); got != tt.want {
+			t.Errorf("Inline(%#v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBindParam(t *testing.T) {
+	query, args := Build(&Binary{Operator: "=", Prec: PREC_EQUALITY, Left: col("name"), Right: &Param{Param: "?", Value: "O'Brien"}})
+	if query != "name = ?" || len(args) != 1 || args[0] != "O'Brien" {
+		t.Errorf("Build() = %q %v, want \"name = ?\" [O'Brien]", query, args)
+	}
+	if got := Inline(&Param{Param: "?", Value: "O'Brien"}); got != "'O''Brien'" {
+		t.Errorf("Inline() = %q, want the quoted literal", got)
+	}
+}
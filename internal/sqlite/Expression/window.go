@@ -0,0 +1,100 @@
+package expr
+
+// Ordering is a term of an ORDER BY inside a window definition
+type Ordering struct {
+	Expression Node
+	Direction  string // ASC, DESC or ""
+}
+
+// FrameBound is a boundary of a window frame
+type FrameBound struct {
+	Kind   string // UNBOUNDED PRECEDING, PRECEDING, CURRENT ROW, FOLLOWING or UNBOUNDED FOLLOWING
+	Offset Node   // the offset of PRECEDING and FOLLOWING
+}
+
+func (b *FrameBound) render(w *Writer) {
+	if b.Offset != nil {
+		w.Operand(b.Offset, PREC_ATOM, false)
+		w.WriteByte(' ')
+	}
+	w.WriteString(b.Kind)
+}
+
+// Frame is the frame of a window, unit is ROWS, RANGE or GROUPS
+type Frame struct {
+	Unit    string
+	Start   *FrameBound
+	End     *FrameBound // nil for a frame without BETWEEN
+	Exclude string      // NO OTHERS, CURRENT ROW, GROUP, TIES or ""
+}
+
+func (f *Frame) render(w *Writer) {
+	w.WriteString(f.Unit + " ")
+	if f.End != nil {
+		w.WriteString("BETWEEN ")
+		f.Start.render(w)
+		w.WriteString(" AND ")
+		f.End.render(w)
+	} else {
+		f.Start.render(w)
+	}
+	if f.Exclude != "" {
+		w.WriteString(" EXCLUDE " + f.Exclude)
+	}
+}
+
+// Window is a window definition, [base] [PARTITION BY ...] [ORDER BY ...] [frame]
+type Window struct {
+	Base        string
+	PartitionBy []Node
+	OrderBy     []Ordering
+	Frame       *Frame
+}
+
+// Render writes the window definition without the parentheses around it
+func (win *Window) Render(w *Writer) {
+	var sep string
+	if win.Base != "" {
+		w.WriteString(win.Base)
+		sep = " "
+	}
+	if len(win.PartitionBy) > 0 {
+		w.WriteString(sep + "PARTITION BY ")
+		w.List(win.PartitionBy)
+		sep = " "
+	}
+	if len(win.OrderBy) > 0 {
+		w.WriteString(sep + "ORDER BY ")
+		for i, order := range win.OrderBy {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.Node(order.Expression)
+			if order.Direction != "" {
+				w.WriteString(" " + order.Direction)
+			}
+		}
+		sep = " "
+	}
+	if win.Frame != nil {
+		w.WriteString(sep)
+		win.Frame.render(w)
+	}
+}
+
+// Over is the OVER clause of a window function, either a name of a window
+// of the WINDOW clause or a window definition
+type Over struct {
+	Name   string
+	Window *Window
+}
+
+func (o *Over) render(w *Writer) {
+	if o.Window == nil {
+		w.WriteString(" OVER " + o.Name)
+		return
+	}
+	w.WriteString(" OVER (")
+	o.Window.Render(w)
+	w.WriteByte(')')
+}
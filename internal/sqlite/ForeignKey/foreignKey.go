@@ -93,7 +93,7 @@ func ParseReference(colName string, foreignTables []any, tag string) (*Reference
 	}
 
 	for _, t := range foreignTables {
-		if foreignTableName == reflectutil.GetStructName(t) {
+		if foreignTableName == reflectutil.GetStructName(t) || foreignTableName == reflectutil.GetTableName(t) {
 			refs.foreignTable = t
 		}
 	}
@@ -192,6 +192,16 @@ func NewReferences(columName string, foreignTablePtr any, foreignColumn string)
 	}
 }
 
+// NewColumnReferences creates the REFERENCES clause of a column constraint
+func NewColumnReferences(columnName string, foreignTablePtr any, foreignColumns []string) *References {
+	return &References{
+		tableTypeReference: false,
+		columnsName:        []string{columnName},
+		foreignTable:       foreignTablePtr,
+		foreignColumnsName: foreignColumns,
+	}
+}
+
 type References struct {
 	tableTypeReference bool
 	columnsName        []string
@@ -210,6 +220,55 @@ func (r *References) GetColumns() []string {
 	return r.columnsName
 }
 
+// IsTableConstraint reports whether the reference is a table FOREIGN KEY constraint
+func (r *References) IsTableConstraint() bool {
+	return r.tableTypeReference
+}
+
+// GetForeignTable returns the referenced table
+func (r *References) GetForeignTable() any {
+	return r.foreignTable
+}
+
+// ForeignTableName returns the SQL name of the referenced table
+func (r *References) ForeignTableName() string {
+	return reflectutil.GetTableName(r.foreignTable)
+}
+
+// ForeignColumns returns the SQL names of the referenced columns
+func (r *References) ForeignColumns() []string {
+	columns := make([]string, len(r.foreignColumnsName))
+	for i, col := range r.foreignColumnsName {
+		columns[i] = utils.ToSnakeCase(col)
+	}
+	return columns
+}
+
+// OnDeleteAction returns the ON DELETE action, "" when unset
+func (r *References) OnDeleteAction() string {
+	return r.onDeleteVal
+}
+
+// OnUpdateAction returns the ON UPDATE action, "" when unset
+func (r *References) OnUpdateAction() string {
+	return r.onUpdateVal
+}
+
+// MatchName returns the MATCH name, "" when unset
+func (r *References) MatchName() string {
+	return r.matchVal
+}
+
+// DeferrableMode returns the initial mode of a DEFERRABLE reference, nil when it isn't one
+func (r *References) DeferrableMode() *string {
+	return r.deferrableVal
+}
+
+// NotDeferrableMode returns the initial mode of a NOT DEFERRABLE reference, nil when it isn't one
+func (r *References) NotDeferrableMode() *string {
+	return r.notDeferrableVal
+}
+
 func (r *References) OnDelete(action string) *References {
 	r.onDeleteVal = action
 	return r
@@ -264,12 +323,15 @@ func (r *References) Build() string {
 		prefix         string
 		colName        string
 		actions        string
-		tableName      = reflectutil.GetStructName(r.foreignTable)
+		tableName      = reflectutil.GetTableName(r.foreignTable)
 		foreignColName = reflectutil.GetStructFieldsNames(r.foreignTable)
 	)
 
 	for _, col := range r.foreignColumnsName {
-		if !slices.Contains(foreignColName, col) {
+		// Tables referenced only by name have no known columns to check against
+		if len(foreignColName) > 0 && !slices.ContainsFunc(foreignColName, func(name string) bool {
+			return col == name || utils.ToSnakeCase(col) == utils.ToSnakeCase(name)
+		}) {
 			panic(fmt.Errorf("column '%s' not found in foreign table '%s'", col, tableName))
 		}
 	}
@@ -306,7 +368,7 @@ func (r *References) Build() string {
 		}
 	}
 
-	return fmt.Sprintf("%sREFERENCES %s%s%s", prefix, utils.ToSnakeCase(tableName), colName, actions)
+	return fmt.Sprintf("%sREFERENCES %s%s%s", prefix, tableName, colName, actions)
 }
 
 func rowAction(value string) (string, error) {
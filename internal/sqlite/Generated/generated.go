@@ -29,8 +29,8 @@ func NewGenerated(always bool, expr *expr.Expression, storage StorageType) strin
 	}
 
 	if storage != NO_STORAGE {
-		stored = storage.String()
+		stored = " " + storage.String()
 	}
 
-	return fmt.Sprintf("%sAS (%s)%s", alw, expr.Build(), stored)
+	return fmt.Sprintf("%sAS (%s)%s", alw, expr.Inline(), stored)
 }
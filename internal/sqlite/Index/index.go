@@ -2,6 +2,7 @@ package index
 
 import (
 	"fmt"
+	"strings"
 
 	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
 	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
@@ -41,17 +42,58 @@ func (i *Index) Schema(schemaName string) *Index {
 	return i
 }
 
+// Columns appends indexed columns to the index
+func (i *Index) Columns(indexCols ...*idxcol.IndexedColumn) *Index {
+	i.columns = append(i.columns, indexCols...)
+	return i
+}
+
 func (i *Index) Where(expression *expr.Expression) *Index {
 	i.where = expression
 	return i
 }
 
+// Name returns the name of the index
+func (i *Index) Name() string {
+	return i.name
+}
+
+// Table returns the indexed table
+func (i *Index) Table() any {
+	return i.table
+}
+
+// IsUnique reports whether it's a UNIQUE index
+func (i *Index) IsUnique() bool {
+	return i.unique
+}
+
+// IsIfNotExists reports whether the index is created with IF NOT EXISTS
+func (i *Index) IsIfNotExists() bool {
+	return i.ifNotExists
+}
+
+// SchemaName returns the schema of the index, "" when unset
+func (i *Index) SchemaName() string {
+	return i.schemaName
+}
+
+// IndexedColumns returns the columns of the index
+func (i *Index) IndexedColumns() []*idxcol.IndexedColumn {
+	return i.columns
+}
+
+// WhereExpression returns the WHERE clause of a partial index, nil when unset
+func (i *Index) WhereExpression() *expr.Expression {
+	return i.where
+}
+
 func (i *Index) Build() string {
 	var (
 		uniq   string
 		ifnot  string
 		schema string
-		col    string
+		cols   []string
 		where  string
 	)
 	if i.unique {
@@ -64,10 +106,10 @@ func (i *Index) Build() string {
 		schema = fmt.Sprintf("%s.", i.schemaName)
 	}
 	for _, column := range i.columns {
-		col += fmt.Sprintf("%s, ", column.Build())
+		cols = append(cols, column.Build())
 	}
 	if i.where != nil {
-		where = fmt.Sprintf(" WHERE %s", i.where.Build())
+		where = fmt.Sprintf(" WHERE %s", i.where.Inline())
 	}
-	return fmt.Sprintf("CREATE %sINDEX %s%s%s ON %s (%s)%s", uniq, ifnot, schema, i.name, reflectutil.GetStructName(i.table), col, where)
+	return fmt.Sprintf("CREATE %sINDEX %s%s%s ON %s (%s)%s", uniq, ifnot, schema, i.name, reflectutil.GetTableName(i.table), strings.Join(cols, ", "), where)
 }
@@ -46,6 +46,29 @@ func (i *IndexedColumn) DESC() *IndexedColumn {
 	return i
 }
 
+// ColumnName returns the SQL name of the column, "" for an expression
+func (i *IndexedColumn) ColumnName() string {
+	if i.name == "" {
+		return ""
+	}
+	return utils.ToSnakeCase(i.name)
+}
+
+// Expression returns the indexed expression, nil for a column
+func (i *IndexedColumn) Expression() *expr.Expression {
+	return i.expression
+}
+
+// Collation returns the COLLATE name, "" when unset
+func (i *IndexedColumn) Collation() string {
+	return i.collate
+}
+
+// SortOrder returns the sort order, "" when unset
+func (i *IndexedColumn) SortOrder() sortorder.SortOrder {
+	return i.sortOrder
+}
+
 func (i *IndexedColumn) Build() string {
 	var (
 		start  string
@@ -54,7 +77,7 @@ func (i *IndexedColumn) Build() string {
 	)
 
 	if i.name == "" {
-		start = i.expression.Build()
+		start = i.expression.Inline()
 	} else {
 		start = utils.ToSnakeCase(i.name)
 	}
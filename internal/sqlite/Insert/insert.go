@@ -0,0 +1,258 @@
+package insertstmt
+
+import (
+	"fmt"
+
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	returning "github.com/Nevoral/sqlofi/internal/sqlite/Returning"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	set "github.com/Nevoral/sqlofi/internal/sqlite/Set"
+	with "github.com/Nevoral/sqlofi/internal/sqlite/With"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// NewInsert creates an INSERT into the table with the column names
+func NewInsert(table string, columns []string) *Insert {
+	return &Insert{
+		table:   table,
+		columns: columns,
+	}
+}
+
+// Insert is an INSERT statement, its rows come from VALUES, a SELECT or DEFAULT VALUES
+type Insert struct {
+	with          *with.With
+	action        string // OR action, e.g. REPLACE or IGNORE
+	table         string
+	alias         string
+	columns       []string
+	rows          [][]*expr.Expression
+	selectStmt    *selectstmt.Select
+	defaultValues bool
+	upserts       []*Upsert
+	returning     returning.Returning
+}
+
+// With sets the WITH clause of the INSERT statement
+func (i *Insert) With(clause *with.With) *Insert {
+	i.with = clause
+	return i
+}
+
+// Or sets the conflict resolution of INSERT OR action, e.g. REPLACE or IGNORE
+func (i *Insert) Or(action string) *Insert {
+	i.action = action
+	return i
+}
+
+// Alias sets an alias for the table
+func (i *Insert) Alias(alias string) *Insert {
+	i.alias = alias
+	return i
+}
+
+// Columns replaces the column names
+func (i *Insert) Columns(columns ...string) *Insert {
+	i.columns = columns
+	return i
+}
+
+// ColumnNames returns the column names
+func (i *Insert) ColumnNames() []string {
+	return i.columns
+}
+
+// Values appends a row of VALUES
+func (i *Insert) Values(row ...*expr.Expression) *Insert {
+	if len(i.columns) > 0 && len(row) != len(i.columns) {
+		panic(fmt.Errorf("Error VALUES row with %d values for %d columns of %s", len(row), len(i.columns), i.table))
+	}
+	i.rows = append(i.rows, row)
+	return i
+}
+
+// Select sets the SELECT whose rows are inserted
+func (i *Insert) Select(stmt *selectstmt.Select) *Insert {
+	i.selectStmt = stmt
+	return i
+}
+
+// DefaultValues inserts a single row of the column defaults
+func (i *Insert) DefaultValues() *Insert {
+	i.defaultValues = true
+	return i
+}
+
+// OnConflict appends an upsert clause, only the last one can be without a conflict target
+func (i *Insert) OnConflict(upsert *Upsert) *Insert {
+	if n := len(i.upserts); n > 0 && len(i.upserts[n-1].target) == 0 {
+		panic(fmt.Errorf("Error ON CONFLICT after an ON CONFLICT without a conflict target"))
+	}
+	i.upserts = append(i.upserts, upsert)
+	return i
+}
+
+// Returning sets the RETURNING clause
+func (i *Insert) Returning(columns ...*selectstmt.ResultColumn) *Insert {
+	i.returning = columns
+	return i
+}
+
+// Render writes the INSERT statement
+func (i *Insert) Render(w *expr.Writer) {
+	sources := len(i.rows)
+	if sources > 0 {
+		sources = 1
+	}
+	if i.selectStmt != nil {
+		sources++
+	}
+	if i.defaultValues {
+		sources++
+	}
+	if sources != 1 {
+		panic(fmt.Errorf("Error INSERT into %s needs exactly one of VALUES, SELECT or DEFAULT VALUES", i.table))
+	}
+	if i.defaultValues && len(i.upserts) > 0 {
+		panic(fmt.Errorf("Error INSERT into %s with DEFAULT VALUES can't have ON CONFLICT", i.table))
+	}
+
+	if i.with != nil {
+		i.with.Render(w)
+	}
+
+	w.WriteString("INSERT ")
+	if i.action != "" {
+		w.WriteString("OR " + i.action + " ")
+	}
+	w.WriteString("INTO " + i.table)
+	if i.alias != "" {
+		w.WriteString(" AS " + i.alias)
+	}
+	if len(i.columns) > 0 && !i.defaultValues {
+		w.WriteString(" (" + utils.Join(i.columns, ", ") + ")")
+	}
+
+	switch {
+	case i.defaultValues:
+		w.WriteString(" DEFAULT VALUES")
+	case i.selectStmt != nil && len(i.upserts) > 0:
+		// ON CONFLICT right after the SELECT would be parsed as a join constraint,
+		// the SQLite documentation advises a WHERE clause in between
+		w.WriteString(" SELECT * FROM (")
+		i.selectStmt.Render(w)
+		w.WriteString(") WHERE true")
+	case i.selectStmt != nil:
+		w.WriteByte(' ')
+		i.selectStmt.Render(w)
+	default:
+		w.WriteString(" VALUES ")
+		for r, row := range i.rows {
+			if r > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteByte('(')
+			for v, value := range row {
+				if v > 0 {
+					w.WriteString(", ")
+				}
+				value.Render(w)
+			}
+			w.WriteByte(')')
+		}
+	}
+
+	for _, upsert := range i.upserts {
+		upsert.Render(w)
+	}
+	i.returning.Render(w)
+}
+
+// Build returns the SQL of the INSERT statement and the bound values
+func (i *Insert) Build() (string, []any) {
+	w := expr.NewWriter(false)
+	i.Render(w)
+	return w.String(), w.Args()
+}
+
+// NewUpsert creates an ON CONFLICT clause for the conflict target columns,
+// without columns it handles any uniqueness conflict
+func NewUpsert(target []string) *Upsert {
+	return &Upsert{
+		target: target,
+	}
+}
+
+// Upsert is the ON CONFLICT clause of an INSERT
+type Upsert struct {
+	target      []string
+	targetWhere *expr.Expression
+	doNothing   bool
+	assignments []*set.Assignment
+	where       *expr.Expression
+}
+
+// TargetWhere sets the WHERE of the conflict target, it matches a partial unique index
+func (u *Upsert) TargetWhere(condition *expr.Expression) *Upsert {
+	u.targetWhere = condition
+	return u
+}
+
+// DoNothing skips the conflicting row
+func (u *Upsert) DoNothing() *Upsert {
+	u.doNothing = true
+	u.assignments = nil
+	return u
+}
+
+// DoUpdate updates the conflicting row, the inserted values are available as excluded.column
+func (u *Upsert) DoUpdate(assignments ...*set.Assignment) *Upsert {
+	u.doNothing = false
+	u.assignments = assignments
+	return u
+}
+
+// Where sets the WHERE of DO UPDATE, rows not matching it aren't updated
+func (u *Upsert) Where(condition *expr.Expression) *Upsert {
+	u.where = condition
+	return u
+}
+
+// Render writes " ON CONFLICT ..." of the upsert
+func (u *Upsert) Render(w *expr.Writer) {
+	w.WriteString(" ON CONFLICT")
+	if len(u.target) > 0 {
+		w.WriteString(" (" + utils.Join(u.target, ", ") + ")")
+		if u.targetWhere != nil {
+			w.WriteString(" WHERE ")
+			u.targetWhere.Render(w)
+		}
+	} else if u.targetWhere != nil {
+		panic(fmt.Errorf("Error ON CONFLICT WHERE without a conflict target"))
+	}
+
+	if u.doNothing || len(u.assignments) == 0 {
+		if u.where != nil {
+			panic(fmt.Errorf("Error ON CONFLICT DO NOTHING with WHERE, it belongs to DO UPDATE"))
+		}
+		w.WriteString(" DO NOTHING")
+		return
+	}
+	if len(u.target) == 0 {
+		panic(fmt.Errorf("Error ON CONFLICT DO UPDATE without a conflict target"))
+	}
+
+	w.WriteString(" DO UPDATE ")
+	set.Render(w, u.assignments)
+	if u.where != nil {
+		w.WriteString(" WHERE ")
+		u.where.Render(w)
+	}
+}
+
+// Build returns the SQL of the upsert clause and the bound values
+func (u *Upsert) Build() (string, []any) {
+	w := expr.NewWriter(false)
+	u.Render(w)
+	return w.String(), w.Args()
+}
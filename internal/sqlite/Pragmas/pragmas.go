@@ -34,6 +34,26 @@ func (p *Pragma) ValueType(value string) *Pragma {
 	return p
 }
 
+// SchemaName returns the schema of the pragma, "" when unset
+func (p *Pragma) SchemaName() string {
+	return p.schemaName
+}
+
+// Name returns the name of the pragma
+func (p *Pragma) Name() string {
+	return p.name
+}
+
+// Value returns the value assigned with "=", "" when unset
+func (p *Pragma) Value() string {
+	return p.eqValue
+}
+
+// Argument returns the value passed in parentheses, "" when unset
+func (p *Pragma) Argument() string {
+	return p.colValue
+}
+
 func (p *Pragma) Build() string {
 	var (
 		sch   string
@@ -47,7 +67,7 @@ func (p *Pragma) Build() string {
 		value = " = " + p.eqValue
 	}
 	if p.colValue != "" {
-		value = " (" + p.eqValue + ")"
+		value = " (" + p.colValue + ")"
 	}
 	return fmt.Sprintf("PRAGMA %s%s%s", sch, p.name, value)
 }
@@ -24,6 +24,16 @@ func (t *TablePrimaryKey) OnConflict(conflict string) *TablePrimaryKey {
 	return t
 }
 
+// Columns returns the indexed columns of the key
+func (t *TablePrimaryKey) Columns() []*idxcol.IndexedColumn {
+	return t.indexedColumn
+}
+
+// Conflict returns the ON CONFLICT clause of the key
+func (t *TablePrimaryKey) Conflict() string {
+	return t.conflict
+}
+
 func (t *TablePrimaryKey) Build() string {
 	var (
 		col      []string
@@ -0,0 +1,24 @@
+package returning
+
+import (
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+)
+
+// Returning is the RETURNING clause of INSERT, UPDATE and DELETE
+type Returning []*selectstmt.ResultColumn
+
+// Render writes " RETURNING columns", nothing when there are no columns
+func (r Returning) Render(w *expr.Writer) {
+	if len(r) == 0 {
+		return
+	}
+	w.WriteString(" RETURNING")
+	for i, col := range r {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteByte(' ')
+		col.Render(w)
+	}
+}
@@ -2,9 +2,9 @@ package selectstmt
 
 import (
 	"fmt"
-	"strings"
 
 	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	with "github.com/Nevoral/sqlofi/internal/sqlite/With"
 	"github.com/Nevoral/sqlofi/internal/utils"
 )
 
@@ -67,23 +67,26 @@ func (r *ResultColumn) Alias(alias string) *ResultColumn {
 	return r
 }
 
-// Build returns the SQL representation of the result column
-func (r *ResultColumn) Build() string {
+// Render writes the SQL representation of the result column
+func (r *ResultColumn) Render(w *expr.Writer) {
 	switch r.columnType {
 	case EXPRESSION:
+		w.Node(r.expression)
 		if r.alias != "" {
-			return fmt.Sprintf("%s AS %s", r.expression.Build(), r.alias)
+			w.WriteString(" AS " + r.alias)
 		}
-		return r.expression.Build()
 	case WILDCARD:
-		return "*"
+		w.WriteString("*")
 	case TABLE_WILDCARD:
-		return fmt.Sprintf("%s.*", utils.ToSnakeCase(r.tableName))
-	default:
-		return ""
+		w.WriteString(utils.ToSnakeCase(r.tableName) + ".*")
 	}
 }
 
+// Build returns the SQL representation of the result column and its arguments
+func (r *ResultColumn) Build() (string, []any) {
+	return build(r)
+}
+
 // From represents a table or subquery in the FROM clause
 type From struct {
 	tableName string
@@ -118,29 +121,33 @@ func (f *From) Join(join *Join) *From {
 	return f
 }
 
-// Build returns the SQL representation of the FROM clause
-func (f *From) Build() string {
-	var source string
-	if f.tableName != "" {
-		source = utils.ToSnakeCase(f.tableName)
-	} else if f.subquery != nil {
-		source = fmt.Sprintf("(%s)", f.subquery.Build())
+// Render writes the SQL representation of the FROM clause
+func (f *From) Render(w *expr.Writer) {
+	renderSource(w, f.tableName, f.subquery, f.alias)
+	for _, join := range f.joins {
+		w.WriteByte(' ')
+		join.Render(w)
 	}
+}
 
-	if f.alias != "" {
-		source = fmt.Sprintf("%s AS %s", source, f.alias)
-	}
+// Build returns the SQL representation of the FROM clause and its arguments
+func (f *From) Build() (string, []any) {
+	return build(f)
+}
 
-	if len(f.joins) == 0 {
-		return source
+// renderSource writes a table or a parenthesized subquery with its alias
+func renderSource(w *expr.Writer, tableName string, subquery *Select, alias string) {
+	if tableName != "" {
+		w.WriteString(utils.ToSnakeCase(tableName))
+	} else if subquery != nil {
+		w.WriteByte('(')
+		subquery.Render(w)
+		w.WriteByte(')')
 	}
 
-	var joins []string
-	for _, join := range f.joins {
-		joins = append(joins, join.Build())
+	if alias != "" {
+		w.WriteString(" AS " + alias)
 	}
-
-	return fmt.Sprintf("%s %s", source, strings.Join(joins, " "))
 }
 
 // Join represents a JOIN clause
@@ -187,31 +194,23 @@ func (j *Join) Using(columns ...string) *Join {
 	return j
 }
 
-// Build returns the SQL representation of the JOIN
-func (j *Join) Build() string {
-	var source string
-	if j.tableName != "" {
-		source = utils.ToSnakeCase(j.tableName)
-	} else if j.subquery != nil {
-		source = fmt.Sprintf("(%s)", j.subquery.Build())
-	}
-
-	if j.alias != "" {
-		source = fmt.Sprintf("%s AS %s", source, j.alias)
-	}
+// Render writes the SQL representation of the JOIN
+func (j *Join) Render(w *expr.Writer) {
+	w.WriteString(j.joinType + " ")
+	renderSource(w, j.tableName, j.subquery, j.alias)
 
-	var condition string
 	if j.on != nil {
-		condition = fmt.Sprintf(" ON %s", j.on.Build())
+		w.WriteString(" ON ")
+		w.Node(j.on)
 	} else if len(j.using) > 0 {
 		// Convert column names to snake_case
-		for i, col := range j.using {
-			j.using[i] = utils.ToSnakeCase(col)
-		}
-		condition = fmt.Sprintf(" USING (%s)", strings.Join(j.using, ", "))
+		fmt.Fprintf(w, " USING (%s)", utils.Join(j.using, ", "))
 	}
+}
 
-	return fmt.Sprintf("%s %s%s", j.joinType, source, condition)
+// Build returns the SQL representation of the JOIN and its arguments
+func (j *Join) Build() (string, []any) {
+	return build(j)
 }
 
 // Select represents a SELECT statement
@@ -228,6 +227,21 @@ type Select struct {
 	hasLimit      bool
 	hasOffset     bool
 	statement     string // Used for raw SQL statements
+	compounds     []*compound
+	windows       []*namedWindow
+	with          *with.With
+}
+
+// namedWindow is a window of the WINDOW clause
+type namedWindow struct {
+	name   string
+	window *expr.Window
+}
+
+// compound is a SELECT joined to the statement by a compound operator
+type compound struct {
+	operator string
+	sel      *Select
 }
 
 // OrderBy represents an ORDER BY clause
@@ -244,21 +258,44 @@ func NewOrderBy(expression *expr.Expression, direction string) *OrderBy {
 	}
 }
 
-// Build returns the SQL representation of the ORDER BY clause
-func (o *OrderBy) Build() string {
-	if o.direction == "" {
-		return o.expression.Build()
+// Render writes the SQL representation of the ORDER BY clause
+func (o *OrderBy) Render(w *expr.Writer) {
+	w.Node(o.expression)
+	if o.direction != "" {
+		w.WriteString(" " + o.direction)
 	}
-	return fmt.Sprintf("%s %s", o.expression.Build(), o.direction)
 }
 
-// NewSelect creates a new SELECT statement from a raw SQL string
+// Expression returns the ordered expression
+func (o *OrderBy) Expression() *expr.Expression {
+	return o.expression
+}
+
+// Direction returns ASC, DESC or "" when unset
+func (o *OrderBy) Direction() string {
+	return o.direction
+}
+
+// Build returns the SQL representation of the ORDER BY clause and its arguments
+func (o *OrderBy) Build() (string, []any) {
+	return build(o)
+}
+
+// NewSelect creates a new SELECT statement, selectType is DISTINCT, ALL or ""
 func NewSelect(selectType string, columns []*ResultColumn) *Select {
 	return &Select{
+		selectType:    selectType,
 		resultColumns: columns,
 	}
 }
 
+// NewRawSelect creates a SELECT statement from a raw SQL string
+func NewRawSelect(statement string) *Select {
+	return &Select{
+		statement: statement,
+	}
+}
+
 // From sets the FROM clause of the SELECT statement
 func (s *Select) From(from *From) *Select {
 	s.from = from
@@ -303,103 +340,199 @@ func (s *Select) Offset(offset int) *Select {
 	return s
 }
 
-// Build returns the SQL representation of the SELECT statement
-func (s *Select) Build() string {
-	// If a raw statement was provided, return it
+// renderCore writes the SELECT statement without its compound operators, ORDER BY, LIMIT and OFFSET
+func (s *Select) renderCore(w *expr.Writer) {
+	// If a raw statement was provided, write it
 	if s.statement != "" {
-		return s.statement
+		w.WriteString(s.statement)
+		return
 	}
 
-	var parts []string
-
 	// SELECT part
-	var selectPart string
+	w.WriteString("SELECT")
 	if s.selectType != "" {
-		selectPart = fmt.Sprintf("SELECT %s", s.selectType)
-	} else {
-		selectPart = "SELECT"
+		w.WriteString(" " + s.selectType)
 	}
-	parts = append(parts, selectPart)
 
 	// Result columns
 	if len(s.resultColumns) == 0 {
 		// Default to wildcard if no columns specified
-		parts = append(parts, "*")
+		w.WriteString(" *")
 	} else {
-		var columns []string
-		for _, col := range s.resultColumns {
-			columns = append(columns, col.Build())
+		for i, col := range s.resultColumns {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteByte(' ')
+			col.Render(w)
 		}
-		parts = append(parts, strings.Join(columns, ", "))
 	}
 
 	// FROM clause
 	if s.from != nil {
-		parts = append(parts, fmt.Sprintf("FROM %s", s.from.Build()))
+		w.WriteString(" FROM ")
+		s.from.Render(w)
 	}
 
 	// WHERE clause
 	if s.where != nil {
-		parts = append(parts, fmt.Sprintf("WHERE %s", s.where.Build()))
+		w.WriteString(" WHERE ")
+		w.Node(s.where)
 	}
 
 	// GROUP BY clause
 	if len(s.groupBy) > 0 {
-		var expressions []string
-		for _, expr := range s.groupBy {
-			expressions = append(expressions, expr.Build())
+		w.WriteString(" GROUP BY ")
+		for i, expression := range s.groupBy {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.Node(expression)
 		}
-		parts = append(parts, fmt.Sprintf("GROUP BY %s", strings.Join(expressions, ", ")))
 	}
 
 	// HAVING clause
 	if s.having != nil {
-		parts = append(parts, fmt.Sprintf("HAVING %s", s.having.Build()))
+		w.WriteString(" HAVING ")
+		w.Node(s.having)
+	}
+
+	// WINDOW clause
+	for i, win := range s.windows {
+		if i == 0 {
+			w.WriteString(" WINDOW ")
+		} else {
+			w.WriteString(", ")
+		}
+		w.WriteString(win.name + " AS (")
+		win.window.Render(w)
+		w.WriteByte(')')
+	}
+}
+
+// Render writes the SQL representation of the SELECT statement
+func (s *Select) Render(w *expr.Writer) {
+	// WITH clause, the common table expressions are visible to the whole compound
+	if s.with != nil {
+		s.with.Render(w)
+	}
+
+	s.renderCore(w)
+
+	// Compound operators, the ORDER BY, LIMIT and OFFSET below apply to the whole compound
+	for _, c := range s.compounds {
+		w.WriteString(" " + c.operator + " ")
+		c.sel.renderCore(w)
 	}
 
 	// ORDER BY clause
 	if len(s.orderBy) > 0 {
-		var orders []string
-		for _, order := range s.orderBy {
-			orders = append(orders, order.Build())
+		w.WriteString(" ORDER BY ")
+		for i, order := range s.orderBy {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			order.Render(w)
 		}
-		parts = append(parts, fmt.Sprintf("ORDER BY %s", strings.Join(orders, ", ")))
 	}
 
 	// LIMIT clause
 	if s.hasLimit {
-		parts = append(parts, fmt.Sprintf("LIMIT %d", s.limit))
+		fmt.Fprintf(w, " LIMIT %d", s.limit)
 	}
 
 	// OFFSET clause
 	if s.hasOffset {
-		parts = append(parts, fmt.Sprintf("OFFSET %d", s.offset))
+		fmt.Fprintf(w, " OFFSET %d", s.offset)
 	}
+}
 
-	return strings.Join(parts, " ")
+// Build returns the SQL representation of the SELECT statement with parameters and their arguments
+func (s *Select) Build() (string, []any) {
+	return build(s)
 }
 
-// Window is not implemented yet
-func (s *Select) Window(expressions ...*expr.Expression) *Select {
-	return s
+// Inline returns the SQL representation of the SELECT statement with the bound values
+// written as literals, e.g. for CREATE VIEW
+func (s *Select) Inline() string {
+	w := expr.NewWriter(true)
+	s.Render(w)
+	return w.String()
 }
 
-// Union is not implemented yet
-func (s *Select) Union(expressions ...*expr.Expression) *Select {
-	return s
+// build renders a part of the statement with parameters
+func build(part interface{ Render(w *expr.Writer) }) (string, []any) {
+	w := expr.NewWriter(false)
+	part.Render(w)
+	return w.String(), w.Args()
 }
 
-// UnionAll is not implemented yet
-func (s *Select) UnionAll(expressions ...*expr.Expression) *Select {
+// With sets the WITH clause of the SELECT statement
+func (s *Select) With(clause *with.With) *Select {
+	s.with = clause
 	return s
 }
 
-// Intersect is not implemented yet
-func (s *Select) Intersect(expressions ...*expr.Expression) *Select {
+// Window adds a named window to the WINDOW clause, window functions refer to it by the name
+func (s *Select) Window(name string, window *expr.Window) *Select {
+	s.windows = append(s.windows, &namedWindow{name: name, window: window})
 	return s
 }
 
-// Except is not implemented yet
-func (s *Select) Except(expressions ...*expr.Expression) *Select {
+// Union appends a UNION of the other SELECT, it removes duplicate rows
+func (s *Select) Union(other *Select) *Select {
+	return s.compound("UNION", other)
+}
+
+// UnionAll appends a UNION ALL of the other SELECT, it keeps duplicate rows
+func (s *Select) UnionAll(other *Select) *Select {
+	return s.compound("UNION ALL", other)
+}
+
+// Intersect appends an INTERSECT of the other SELECT
+func (s *Select) Intersect(other *Select) *Select {
+	return s.compound("INTERSECT", other)
+}
+
+// Except appends an EXCEPT of the other SELECT
+func (s *Select) Except(other *Select) *Select {
+	return s.compound("EXCEPT", other)
+}
+
+// compound joins the other SELECT, ORDER BY, LIMIT and OFFSET are only allowed on the
+// first one where they apply to the whole compound. Other compound SELECTs are nested
+// as FROM subqueries.
+func (s *Select) compound(operator string, other *Select) *Select {
+	if other == nil {
+		panic(fmt.Errorf("Error %s without a SELECT", operator))
+	}
+	if len(other.orderBy) > 0 || other.hasLimit || other.hasOffset {
+		panic(fmt.Errorf("Error %s of a SELECT with ORDER BY, LIMIT or OFFSET, they belong to the first SELECT", operator))
+	}
+	if other.with != nil {
+		panic(fmt.Errorf("Error %s of a SELECT with WITH, it belongs to the first SELECT", operator))
+	}
+	if len(other.compounds) > 0 {
+		panic(fmt.Errorf("Error %s of a compound SELECT, use it as a subquery in FROM", operator))
+	}
+	if want, got := s.columnCount(), other.columnCount(); want >= 0 && got >= 0 && want != got {
+		panic(fmt.Errorf("Error %s of SELECTs with %d and %d result columns", operator, want, got))
+	}
+
+	s.compounds = append(s.compounds, &compound{operator: operator, sel: other})
 	return s
 }
+
+// columnCount returns the number of result columns, -1 when it isn't known
+// without the database, e.g. for wildcards and raw statements
+func (s *Select) columnCount() int {
+	if s.statement != "" || len(s.resultColumns) == 0 {
+		return -1
+	}
+	for _, col := range s.resultColumns {
+		if col.columnType != EXPRESSION {
+			return -1
+		}
+	}
+	return len(s.resultColumns)
+}
@@ -0,0 +1,61 @@
+package set
+
+import (
+	"fmt"
+
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+)
+
+// NewAssignment creates an assignment of the SET clause, more columns are assigned
+// from a row value, e.g. (a, b) = (1, 2)
+func NewAssignment(columns []string, value *expr.Expression) *Assignment {
+	if len(columns) == 0 {
+		panic(fmt.Errorf("Error SET without a column"))
+	}
+	return &Assignment{
+		columns: columns,
+		value:   value,
+	}
+}
+
+// Assignment is a column = expr or (column, ...) = expr of the SET clause
+type Assignment struct {
+	columns []string
+	value   *expr.Expression
+}
+
+// Columns returns the assigned columns
+func (a *Assignment) Columns() []string {
+	return a.columns
+}
+
+func (a *Assignment) Render(w *expr.Writer) {
+	if len(a.columns) == 1 {
+		w.WriteString(a.columns[0])
+	} else {
+		w.WriteByte('(')
+		for i, column := range a.columns {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(column)
+		}
+		w.WriteByte(')')
+	}
+	w.WriteString(" = ")
+	a.value.Render(w)
+}
+
+// Render writes the SET clause with the assignments
+func Render(w *expr.Writer, assignments []*Assignment) {
+	if len(assignments) == 0 {
+		panic(fmt.Errorf("Error SET without an assignment"))
+	}
+	w.WriteString("SET ")
+	for i, assignment := range assignments {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		assignment.Render(w)
+	}
+}
@@ -7,25 +7,36 @@ import (
 	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
 	check "github.com/Nevoral/sqlofi/internal/sqlite/Check"
 	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
 	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
 	foreignkey "github.com/Nevoral/sqlofi/internal/sqlite/ForeignKey"
+	index "github.com/Nevoral/sqlofi/internal/sqlite/Index"
+	idxcol "github.com/Nevoral/sqlofi/internal/sqlite/IndexedColumn"
 	primarykey "github.com/Nevoral/sqlofi/internal/sqlite/PrimaryKey"
 	selectstmst "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	sortorder "github.com/Nevoral/sqlofi/internal/sqlite/SortOrder"
 	unique "github.com/Nevoral/sqlofi/internal/sqlite/Unique"
-	"github.com/Nevoral/sqlofi/internal/utils"
 )
 
 func NewTable(model any, foreignTablePtrs []any) *Table {
 	return &Table{
-		name:          reflectutil.GetStructName(model),
+		name:          reflectutil.GetTableName(model),
 		model:         model,
 		foreignTables: foreignTablePtrs,
 	}
 }
 
+// NewDynamicTable creates a table without a Go struct, its columns are added with Column
+func NewDynamicTable(name string) *Table {
+	return &Table{
+		name: name,
+	}
+}
+
 type Table struct {
 	model         any
 	foreignTables []any
+	columns       []*column.Column
 
 	temporary   bool
 	schema      string
@@ -33,12 +44,37 @@ type Table struct {
 	ifNotExists bool
 	selectSTMT  *selectstmst.Select
 
-	constraints []string
+	constraints []*Constraint
 
 	withoutRowID bool
 	strict       bool
 }
 
+// Model returns the struct the table is defined by
+func (t *Table) Model() any {
+	return t.model
+}
+
+// TableName returns the SQL name of the table
+func (t *Table) TableName() string {
+	return t.name
+}
+
+// ColumnNames returns the SQL names of the table columns
+func (t *Table) ColumnNames() []string {
+	var names []string
+	for _, col := range t.getColumns(nil) {
+		names = append(names, col.Name())
+	}
+	return names
+}
+
+// Column appends columns defined without a struct field, after the model columns
+func (t *Table) Column(columns ...*column.Column) *Table {
+	t.columns = append(t.columns, columns...)
+	return t
+}
+
 func (t *Table) Temporary() *Table {
 	t.temporary = true
 	return t
@@ -69,63 +105,111 @@ func (t *Table) Strict() *Table {
 	return t
 }
 
-func (t *Table) PrimaryKey(constraintName string, key *primarykey.TablePrimaryKey) *Table {
-	if constraintName == "" {
-		t.constraints = append(t.constraints, key.Build())
-	} else {
-		t.constraints = append(t.constraints,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				key.Build(),
-			),
-		)
+// Constraint is a table constraint, exactly one of its kinds is set
+type Constraint struct {
+	Name       string
+	PrimaryKey *primarykey.TablePrimaryKey
+	Unique     *unique.TableUnique
+	Check      *expr.Expression
+	ForeignKey *foreignkey.References
+}
+
+// Build returns the SQL of the constraint including its CONSTRAINT name
+func (c *Constraint) Build() string {
+	var body string
+	switch {
+	case c.PrimaryKey != nil:
+		body = c.PrimaryKey.Build()
+	case c.Unique != nil:
+		body = c.Unique.Build()
+	case c.Check != nil:
+		body = check.NewCheck(c.Check).Build()
+	case c.ForeignKey != nil:
+		body = c.ForeignKey.Build()
 	}
+
+	if c.Name == "" {
+		return body
+	}
+	return fmt.Sprintf("CONSTRAINT %s %s", c.Name, body)
+}
+
+func (t *Table) PrimaryKey(constraintName string, key *primarykey.TablePrimaryKey) *Table {
+	t.constraints = append(t.constraints, &Constraint{Name: constraintName, PrimaryKey: key})
 	return t
 }
 
 func (t *Table) Unique(constraintName string, unique *unique.TableUnique) *Table {
-	if constraintName == "" {
-		t.constraints = append(t.constraints, unique.Build())
-	} else {
-		t.constraints = append(t.constraints,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				unique.Build(),
-			),
-		)
-	}
+	t.constraints = append(t.constraints, &Constraint{Name: constraintName, Unique: unique})
 	return t
 }
 
 func (t *Table) Check(constraintName string, expression *expr.Expression) *Table {
-	if constraintName == "" {
-		t.constraints = append(t.constraints, check.NewCheck(expression).Build())
-	} else {
-		t.constraints = append(t.constraints,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				check.NewCheck(expression).Build(),
-			),
-		)
-	}
+	t.constraints = append(t.constraints, &Constraint{Name: constraintName, Check: expression})
 	return t
 }
 
 func (t *Table) ForeignKey(constraintName string, key *foreignkey.References) *Table {
-	if constraintName == "" {
-		t.constraints = append(t.constraints, key.Build())
-	} else {
-		t.constraints = append(t.constraints,
-			fmt.Sprintf("CONSTRAINT %s %s",
-				constraintName,
-				key.Build(),
-			),
-		)
-	}
+	t.constraints = append(t.constraints, &Constraint{Name: constraintName, ForeignKey: key})
 	return t
 }
 
+// Constraints returns the table constraints in the order they were added
+func (t *Table) Constraints() []*Constraint {
+	return t.constraints
+}
+
+// Columns returns the columns of the table, the model columns first
+func (t *Table) Columns() []*column.Column {
+	return t.getColumns(nil)
+}
+
+// EnumColumns returns the columns like Columns, enum typed columns of the registry get their CHECK constraint
+func (t *Table) EnumColumns(registry enum.Registry) []*column.Column {
+	return t.getColumns(registry)
+}
+
+// SchemaName returns the schema of the table, "" when unset
+func (t *Table) SchemaName() string {
+	return t.schema
+}
+
+// IsTemporary reports whether it's a TEMP table
+func (t *Table) IsTemporary() bool {
+	return t.temporary
+}
+
+// IsIfNotExists reports whether the table is created with IF NOT EXISTS
+func (t *Table) IsIfNotExists() bool {
+	return t.ifNotExists
+}
+
+// IsWithoutRowID reports whether it's a WITHOUT ROWID table
+func (t *Table) IsWithoutRowID() bool {
+	return t.withoutRowID
+}
+
+// IsStrict reports whether it's a STRICT table
+func (t *Table) IsStrict() bool {
+	return t.strict
+}
+
+// SelectStatement returns the statement of a CREATE TABLE ... AS SELECT table, nil otherwise
+func (t *Table) SelectStatement() *selectstmst.Select {
+	return t.selectSTMT
+}
+
 func (t *Table) Build() string {
+	return t.build(nil)
+}
+
+// BuildEnums returns the CREATE TABLE statement like Build, enum typed columns
+// of the registry get a CHECK constraint listing their values
+func (t *Table) BuildEnums(registry enum.Registry) string {
+	return t.build(registry)
+}
+
+func (t *Table) build(registry enum.Registry) string {
 	var (
 		typeTable  = " TABLE"
 		ifNotExist string
@@ -157,18 +241,61 @@ func (t *Table) Build() string {
 			}
 		}
 
-		body = fmt.Sprintf("(\n%s%s\n)%s", t.buildColumnsDefinition(len(t.constraints) > 0), strings.Join(t.constraints, ",\n\t"), options)
+		constraints := make([]string, len(t.constraints))
+		for i, constraint := range t.constraints {
+			constraints[i] = constraint.Build()
+		}
+
+		body = fmt.Sprintf("(\n%s%s\n)%s", t.buildColumnsDefinition(registry, len(t.constraints) > 0), strings.Join(constraints, ",\n\t"), options)
 	} else {
-		body = fmt.Sprintf("AS %s", t.selectSTMT.Build())
+		body = fmt.Sprintf("AS %s", t.selectSTMT.Inline())
 	}
 
-	return fmt.Sprintf("CREATE%s%s %s%s %s", typeTable, ifNotExist, schema, utils.ToSnakeCase(t.name), body)
+	return fmt.Sprintf("CREATE%s%s %s%s %s", typeTable, ifNotExist, schema, t.name, body)
 }
 
-func (t *Table) buildColumnsDefinition(existConstraints bool) string {
+// Indexes returns the indexes declared in the column tags,
+// columns sharing an index name make up one index in declaration order
+func (t *Table) Indexes() []*index.Index {
+	var (
+		indexes []*index.Index
+		byName  = map[string]*index.Index{}
+		columns = map[string][]*idxcol.IndexedColumn{}
+	)
+
+	for _, col := range t.getColumns(nil) {
+		for _, colIdx := range col.Indexes() {
+			idx, ok := byName[colIdx.Name]
+			if !ok {
+				idx = index.NewIndex(t, colIdx.Name, nil)
+				byName[colIdx.Name] = idx
+				indexes = append(indexes, idx)
+			}
+			if colIdx.Unique {
+				idx.Unique()
+			}
+
+			idxColumn := idxcol.NewIndexedColumnNames(col.Name())
+			switch colIdx.SortOrder {
+			case sortorder.ASC:
+				idxColumn.ASC()
+			case sortorder.DESC:
+				idxColumn.DESC()
+			}
+			columns[colIdx.Name] = append(columns[colIdx.Name], idxColumn)
+		}
+	}
+
+	for name, idx := range byName {
+		idx.Columns(columns[name]...)
+	}
+	return indexes
+}
+
+func (t *Table) buildColumnsDefinition(registry enum.Registry, existConstraints bool) string {
 	var (
 		result  = "\t"
-		columns = t.getColumns()
+		columns = t.getColumns(registry)
 	)
 
 	for index, col := range columns {
@@ -181,11 +308,20 @@ func (t *Table) buildColumnsDefinition(existConstraints bool) string {
 	return result
 }
 
-func (t *Table) getColumns() []*column.Column {
+func (t *Table) getColumns(registry enum.Registry) []*column.Column {
 	var columns []*column.Column
 	for _, col := range reflectutil.GetStructFields(t.model) {
-		ref := column.ParseStructField(t.foreignTables, col)
+		ref, err := column.ParseStructField(t.foreignTables, col)
+		if err != nil {
+			panic(err)
+		}
+		if ref == nil {
+			continue
+		}
+		if values, ok := enum.Values(col.Type, registry); ok {
+			ref.Enum("", values)
+		}
 		columns = append(columns, ref)
 	}
-	return columns
+	return append(columns, t.columns...)
 }
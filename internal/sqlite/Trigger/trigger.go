@@ -0,0 +1,192 @@
+package trigger
+
+import (
+	"fmt"
+	"strings"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+)
+
+// Timing is the moment the trigger fires relative to its event
+type Timing string
+
+const (
+	NO_TIMING  Timing = ""
+	BEFORE     Timing = "BEFORE"
+	AFTER      Timing = "AFTER"
+	INSTEAD_OF Timing = "INSTEAD OF"
+)
+
+// Event is the statement that fires the trigger
+type Event string
+
+const (
+	DELETE Event = "DELETE"
+	INSERT Event = "INSERT"
+	UPDATE Event = "UPDATE"
+)
+
+func NewTrigger(name string, timing Timing, event Event, table any) *Trigger {
+	return &Trigger{
+		name:   name,
+		timing: timing,
+		event:  event,
+		table:  table,
+	}
+}
+
+// Trigger represents a CREATE TRIGGER statement
+type Trigger struct {
+	temporary   bool
+	ifNotExists bool
+	schema      string
+	name        string
+	timing      Timing
+	event       Event
+	columns     []string
+	table       any
+	forEachRow  bool
+	when        *expr.Expression
+	statements  []string
+}
+
+func (t *Trigger) Temporary() *Trigger {
+	t.temporary = true
+	return t
+}
+
+func (t *Trigger) IfNotExists() *Trigger {
+	t.ifNotExists = true
+	return t
+}
+
+func (t *Trigger) Schema(schemaName string) *Trigger {
+	t.schema = schemaName
+	return t
+}
+
+// Of limits an UPDATE trigger to updates of the given columns
+func (t *Trigger) Of(columns ...string) *Trigger {
+	if t.event != UPDATE {
+		panic(fmt.Errorf("trigger '%s': OF columns are allowed only for UPDATE triggers", t.name))
+	}
+	t.columns = append(t.columns, columns...)
+	return t
+}
+
+func (t *Trigger) ForEachRow() *Trigger {
+	t.forEachRow = true
+	return t
+}
+
+func (t *Trigger) When(condition *expr.Expression) *Trigger {
+	t.when = condition
+	return t
+}
+
+// Do appends statements to the trigger body, without the closing semicolon
+func (t *Trigger) Do(statements ...string) *Trigger {
+	t.statements = append(t.statements, statements...)
+	return t
+}
+
+// Name returns the name of the trigger
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+// SchemaName returns the schema of the trigger, "" when unset
+func (t *Trigger) SchemaName() string {
+	return t.schema
+}
+
+// IsTemporary reports whether it's a TEMP trigger
+func (t *Trigger) IsTemporary() bool {
+	return t.temporary
+}
+
+// IsIfNotExists reports whether the trigger is created with IF NOT EXISTS
+func (t *Trigger) IsIfNotExists() bool {
+	return t.ifNotExists
+}
+
+// Timing returns when the trigger fires
+func (t *Trigger) Timing() Timing {
+	return t.timing
+}
+
+// Event returns the statement firing the trigger
+func (t *Trigger) Event() Event {
+	return t.event
+}
+
+// ColumnNames returns the OF columns of an UPDATE trigger
+func (t *Trigger) ColumnNames() []string {
+	return t.columns
+}
+
+// Table returns the table of the trigger
+func (t *Trigger) Table() any {
+	return t.table
+}
+
+// IsForEachRow reports whether the trigger is declared FOR EACH ROW
+func (t *Trigger) IsForEachRow() bool {
+	return t.forEachRow
+}
+
+// WhenExpression returns the WHEN condition, nil when unset
+func (t *Trigger) WhenExpression() *expr.Expression {
+	return t.when
+}
+
+// Statements returns the statements of the trigger body
+func (t *Trigger) Statements() []string {
+	return t.statements
+}
+
+func (t *Trigger) Build() string {
+	var (
+		temp    string
+		ifNot   string
+		schema  string
+		timing  string
+		columns string
+		forEach string
+		when    string
+	)
+	if len(t.statements) == 0 {
+		panic(fmt.Errorf("trigger '%s' has no statements", t.name))
+	}
+	if t.temporary {
+		temp = " TEMP"
+	}
+	if t.ifNotExists {
+		ifNot = " IF NOT EXISTS"
+	}
+	if t.schema != "" {
+		schema = t.schema + "."
+	}
+	if t.timing != NO_TIMING {
+		timing = " " + string(t.timing)
+	}
+	if len(t.columns) > 0 {
+		columns = " OF " + strings.Join(t.columns, ", ")
+	}
+	if t.forEachRow {
+		forEach = " FOR EACH ROW"
+	}
+	if t.when != nil {
+		when = fmt.Sprintf(" WHEN %s", t.when.Inline())
+	}
+
+	var body string
+	for _, statement := range t.statements {
+		body += fmt.Sprintf("\n\t%s;", statement)
+	}
+
+	return fmt.Sprintf("CREATE%s TRIGGER%s %s%s%s %s%s ON %s%s%s\nBEGIN%s\nEND",
+		temp, ifNot, schema, t.name, timing, t.event, columns,
+		reflectutil.GetTableName(t.table), forEach, when, body)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	"github.com/Nevoral/sqlofi/internal/utils"
 )
 
 // getSQLiteType returns the appropriate SQLite type for a Go type
@@ -201,8 +202,10 @@ func (s SignedNumber) String() string {
 	return string(s)
 }
 
+// NewDbPath references a column, table is a table name, a model or nil for a bare column.
+// The column of a model is checked to be one of its fields.
 func NewDbPath(schema string, table any, column string) *DbPath {
-	if table != nil {
+	if _, ok := table.(string); !ok && table != nil {
 		if !slices.Contains(reflectutil.GetStructFieldsNames(table), column) {
 			panic("Error column name isn't present in the table")
 		}
@@ -221,25 +224,32 @@ type DbPath struct {
 	column string
 }
 
+// tableName returns the SQL name of the table, a string is used as is and
+// a model is named like in CREATE TABLE
+func (d *DbPath) tableName() string {
+	if name, ok := d.table.(string); ok {
+		return name
+	}
+	return reflectutil.GetTableName(d.table)
+}
+
 func (d *DbPath) StringColumn() string {
-	if d.schema == "" {
-		if d.table == nil {
-			return fmt.Sprintf("%s", d.column)
-		}
-		return fmt.Sprintf("%s.%s", d.table, d.column)
+	column := utils.ToSnakeCase(d.column)
+	if d.table == nil {
+		return column
 	}
-	return fmt.Sprintf("%s.%s.%s", d.schema, d.table, d.column)
+	return fmt.Sprintf("%s.%s", d.StringTable(), column)
 }
 
 func (d *DbPath) StringTable() string {
 	if d.schema == "" {
-		return fmt.Sprintf("%s", d.table)
+		return d.tableName()
 	}
-	return fmt.Sprintf("%s.%s", d.schema, d.table)
+	return fmt.Sprintf("%s.%s", d.schema, d.tableName())
 }
 
 func (d *DbPath) StringSchema() string {
-	return fmt.Sprintf("%s", d.schema)
+	return d.schema
 }
 
 type (
@@ -31,11 +31,28 @@ func (u *TableUnique) OnConflict(conflict string) *TableUnique {
 	return u
 }
 
+// Columns returns the indexed columns of the constraint
+func (u *TableUnique) Columns() []*idxcol.IndexedColumn {
+	return u.indexedColumn
+}
+
+// Conflict returns the ON CONFLICT clause of the constraint
+func (u *TableUnique) Conflict() string {
+	return u.conflict
+}
+
 func (u *TableUnique) Build() string {
-	var col []string
+	var (
+		col      []string
+		conflict string
+	)
 	for _, val := range u.indexedColumn {
 		col = append(col, val.Build())
 	}
 
-	return fmt.Sprintf("UNIQUE (%s) %s", strings.Join(col, ", "), u.conflict)
+	if u.conflict != "" {
+		conflict = " " + u.conflict
+	}
+
+	return fmt.Sprintf("UNIQUE (%s)%s", strings.Join(col, ", "), conflict)
 }
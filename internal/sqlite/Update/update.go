@@ -0,0 +1,153 @@
+package updatestmt
+
+import (
+	"fmt"
+
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	returning "github.com/Nevoral/sqlofi/internal/sqlite/Returning"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	set "github.com/Nevoral/sqlofi/internal/sqlite/Set"
+	with "github.com/Nevoral/sqlofi/internal/sqlite/With"
+)
+
+// NewUpdate creates an UPDATE of the table
+func NewUpdate(table string) *Update {
+	return &Update{
+		table: table,
+	}
+}
+
+// Update is an UPDATE statement
+type Update struct {
+	with        *with.With
+	action      string // OR action, e.g. REPLACE or IGNORE
+	table       string
+	alias       string
+	assignments []*set.Assignment
+	from        *selectstmt.From
+	where       *expr.Expression
+	returning   returning.Returning
+	orderBy     []*selectstmt.OrderBy
+	limit       int
+	offset      int
+	hasLimit    bool
+	hasOffset   bool
+}
+
+// With sets the WITH clause of the UPDATE statement
+func (u *Update) With(clause *with.With) *Update {
+	u.with = clause
+	return u
+}
+
+// Or sets the conflict resolution of UPDATE OR action, e.g. REPLACE or IGNORE
+func (u *Update) Or(action string) *Update {
+	u.action = action
+	return u
+}
+
+// Alias sets an alias for the table
+func (u *Update) Alias(alias string) *Update {
+	u.alias = alias
+	return u
+}
+
+// Set appends assignments to the SET clause
+func (u *Update) Set(assignments ...*set.Assignment) *Update {
+	u.assignments = append(u.assignments, assignments...)
+	return u
+}
+
+// From sets the FROM clause, its tables can be used in SET and WHERE
+func (u *Update) From(from *selectstmt.From) *Update {
+	u.from = from
+	return u
+}
+
+// Where sets the WHERE clause
+func (u *Update) Where(condition *expr.Expression) *Update {
+	u.where = condition
+	return u
+}
+
+// Returning sets the RETURNING clause
+func (u *Update) Returning(columns ...*selectstmt.ResultColumn) *Update {
+	u.returning = columns
+	return u
+}
+
+// OrderBy sets the ORDER BY clause, SQLite needs SQLITE_ENABLE_UPDATE_DELETE_LIMIT for it
+func (u *Update) OrderBy(orderBy ...*selectstmt.OrderBy) *Update {
+	u.orderBy = orderBy
+	return u
+}
+
+// Limit sets the LIMIT clause, SQLite needs SQLITE_ENABLE_UPDATE_DELETE_LIMIT for it
+func (u *Update) Limit(limit int) *Update {
+	u.limit = limit
+	u.hasLimit = true
+	return u
+}
+
+// Offset sets the OFFSET clause, it requires LIMIT
+func (u *Update) Offset(offset int) *Update {
+	u.offset = offset
+	u.hasOffset = true
+	return u
+}
+
+// Render writes the UPDATE statement
+func (u *Update) Render(w *expr.Writer) {
+	if (len(u.orderBy) > 0 || u.hasOffset) && !u.hasLimit {
+		panic(fmt.Errorf("Error UPDATE of %s with ORDER BY or OFFSET without LIMIT", u.table))
+	}
+
+	if u.with != nil {
+		u.with.Render(w)
+	}
+
+	w.WriteString("UPDATE ")
+	if u.action != "" {
+		w.WriteString("OR " + u.action + " ")
+	}
+	w.WriteString(u.table)
+	if u.alias != "" {
+		w.WriteString(" AS " + u.alias)
+	}
+
+	w.WriteByte(' ')
+	set.Render(w, u.assignments)
+
+	if u.from != nil {
+		w.WriteString(" FROM ")
+		u.from.Render(w)
+	}
+	if u.where != nil {
+		w.WriteString(" WHERE ")
+		u.where.Render(w)
+	}
+	u.returning.Render(w)
+
+	if len(u.orderBy) > 0 {
+		w.WriteString(" ORDER BY ")
+		for i, order := range u.orderBy {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			order.Render(w)
+		}
+	}
+	if u.hasLimit {
+		fmt.Fprintf(w, " LIMIT %d", u.limit)
+	}
+	if u.hasOffset {
+		fmt.Fprintf(w, " OFFSET %d", u.offset)
+	}
+}
+
+// Build returns the SQL of the UPDATE statement and the bound values
+func (u *Update) Build() (string, []any) {
+	w := expr.NewWriter(false)
+	u.Render(w)
+	return w.String(), w.Args()
+}
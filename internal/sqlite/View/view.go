@@ -0,0 +1,101 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+)
+
+func NewView(name string, statement *selectstmt.Select) *View {
+	return &View{
+		name:       name,
+		selectSTMT: statement,
+	}
+}
+
+// View represents a CREATE VIEW statement
+type View struct {
+	temporary   bool
+	ifNotExists bool
+	schema      string
+	name        string
+	columns     []string
+	selectSTMT  *selectstmt.Select
+}
+
+func (v *View) Temporary() *View {
+	v.temporary = true
+	return v
+}
+
+func (v *View) IfNotExists() *View {
+	v.ifNotExists = true
+	return v
+}
+
+func (v *View) Schema(schemaName string) *View {
+	v.schema = schemaName
+	return v
+}
+
+// Columns names the columns of the view instead of the result columns of the SELECT
+func (v *View) Columns(columns ...string) *View {
+	v.columns = append(v.columns, columns...)
+	return v
+}
+
+// Name returns the name of the view
+func (v *View) Name() string {
+	return v.name
+}
+
+// SchemaName returns the schema of the view, "" when unset
+func (v *View) SchemaName() string {
+	return v.schema
+}
+
+// IsTemporary reports whether it's a TEMP view
+func (v *View) IsTemporary() bool {
+	return v.temporary
+}
+
+// IsIfNotExists reports whether the view is created with IF NOT EXISTS
+func (v *View) IsIfNotExists() bool {
+	return v.ifNotExists
+}
+
+// ColumnNames returns the named columns of the view
+func (v *View) ColumnNames() []string {
+	return v.columns
+}
+
+// SelectStatement returns the SELECT the view is defined by
+func (v *View) SelectStatement() *selectstmt.Select {
+	return v.selectSTMT
+}
+
+func (v *View) Build() string {
+	var (
+		temp    string
+		ifNot   string
+		schema  string
+		columns string
+	)
+	if v.selectSTMT == nil {
+		panic(fmt.Errorf("view '%s' has no SELECT statement", v.name))
+	}
+	if v.temporary {
+		temp = " TEMP"
+	}
+	if v.ifNotExists {
+		ifNot = " IF NOT EXISTS"
+	}
+	if v.schema != "" {
+		schema = v.schema + "."
+	}
+	if len(v.columns) > 0 {
+		columns = fmt.Sprintf(" (%s)", strings.Join(v.columns, ", "))
+	}
+	return fmt.Sprintf("CREATE%s VIEW%s %s%s%s AS %s", temp, ifNot, schema, v.name, columns, v.selectSTMT.Inline())
+}
@@ -2,27 +2,57 @@ package with
 
 import (
 	"fmt"
-	"strings"
 
 	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
-	selectstmst "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
 	"github.com/Nevoral/sqlofi/internal/utils"
 )
 
-func NewWithClause(recursive bool, tableExpressions ...*TableExpresions) string {
-	withClause := "WITH "
-	if recursive {
-		withClause += "RECURSIVE "
+// NewWithClause creates the WITH prefix of a statement
+func NewWithClause(recursive bool, tableExpressions ...*TableExpresions) *With {
+	if len(tableExpressions) == 0 {
+		panic(fmt.Errorf("Error WITH without a common table expression"))
 	}
+	return &With{
+		recursive:        recursive,
+		tableExpressions: tableExpressions,
+	}
+}
 
-	tableExprs := make([]string, 0, len(tableExpressions))
-	for _, tableExpr := range tableExpressions {
-		tableExprs = append(tableExprs, tableExpr.Build())
+// With is the WITH clause of a SELECT, INSERT, UPDATE or DELETE statement
+type With struct {
+	recursive        bool
+	tableExpressions []*TableExpresions
+}
+
+// IsRecursive reports whether it's WITH RECURSIVE
+func (w *With) IsRecursive() bool {
+	return w.recursive
+}
+
+// TableExpressions returns the common table expressions
+func (w *With) TableExpressions() []*TableExpresions {
+	return w.tableExpressions
+}
+
+// Render writes the WITH clause followed by a space
+func (w *With) Render(wr *expr.Writer) {
+	wr.WriteString("WITH ")
+	if w.recursive {
+		wr.WriteString("RECURSIVE ")
 	}
 
-	return withClause + strings.Join(tableExprs, ", ")
+	for i, tableExpr := range w.tableExpressions {
+		if i > 0 {
+			wr.WriteString(", ")
+		}
+		tableExpr.Render(wr)
+	}
+	wr.WriteByte(' ')
 }
 
+// NewTableExpresion creates a common table expression, table is its name or a model
+// whose table name it takes
 func NewTableExpresion(table any, columns ...string) *TableExpresions {
 	return &TableExpresions{
 		table:        table,
@@ -35,7 +65,7 @@ type TableExpresions struct {
 	table        any
 	columns      []string
 	materialized int8 // -1: NOT MATERIALIZED, 0: default, 1: MATERIALIZED
-	selectStmt   *selectstmst.Select
+	selectStmt   expr.Statement
 }
 
 // Materialized sets the table expression as MATERIALIZED
@@ -51,36 +81,38 @@ func (t *TableExpresions) NotMaterialized() *TableExpresions {
 }
 
 // WithSelect specifies the SELECT statement for this table expression
-func (t *TableExpresions) WithSelect(stmt *selectstmst.Select) *TableExpresions {
+func (t *TableExpresions) WithSelect(stmt expr.Statement) *TableExpresions {
 	t.selectStmt = stmt
 	return t
 }
 
-func (t *TableExpresions) Build() string {
-	tableName := reflectutil.GetStructName(t.table)
+// Name returns the name of the table expression as it's referred to in FROM and JOIN
+func (t *TableExpresions) Name() string {
+	return reflectutil.GetTableName(t.table)
+}
+
+// Render writes name[(columns)] AS [[NOT] MATERIALIZED] (select)
+func (t *TableExpresions) Render(w *expr.Writer) {
+	if t.selectStmt == nil {
+		panic(fmt.Errorf("Error common table expression %s without a SELECT", t.Name()))
+	}
+
+	w.WriteString(t.Name())
 
 	// Build the column list if specified
-	columnList := ""
 	if len(t.columns) > 0 {
-		columnList = "(" + utils.Join(t.columns, ", ") + ")"
+		w.WriteString("(" + utils.Join(t.columns, ", ") + ")")
 	}
 
-	// Build the materialized clause
-	materialized := ""
+	w.WriteString(" AS ")
 	switch t.materialized {
 	case 1:
-		materialized = " MATERIALIZED"
+		w.WriteString("MATERIALIZED ")
 	case -1:
-		materialized = " NOT MATERIALIZED"
+		w.WriteString("NOT MATERIALIZED ")
 	}
 
-	// Build the select statement
-	if t.selectStmt == nil {
-		return fmt.Sprintf("%s%s AS%s", utils.ToSnakeCase(tableName), columnList, materialized)
-	}
-
-	// We have a SELECT statement
-	selectSQL := t.selectStmt.Build()
-
-	return fmt.Sprintf("%s%s AS%s (%s)", utils.ToSnakeCase(tableName), columnList, materialized, selectSQL)
+	w.WriteByte('(')
+	t.selectStmt.Render(w)
+	w.WriteByte(')')
 }
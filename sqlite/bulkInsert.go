@@ -0,0 +1,113 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// DEFAULT_MAX_VARIABLES is SQLITE_MAX_VARIABLE_NUMBER of SQLite 3.32.0 and newer,
+// older versions default to 999
+const DEFAULT_MAX_VARIABLES = 32766
+
+// BulkOptions configures BulkInsert, the zero value inserts batches of 1000 rows
+type BulkOptions struct {
+	// BatchSize is the number of rows inserted in one transaction, 1000 when it's 0
+	BatchSize int
+	// MaxVariables is the SQLITE_MAX_VARIABLE_NUMBER of the database, DEFAULT_MAX_VARIABLES
+	// when it's 0. A statement binds at most this many values.
+	MaxVariables int
+	// Or is the conflict resolution of INSERT OR, e.g. IGNORE or REPLACE
+	Or ConflictClause
+	// OnConflict is the upsert clause of every statement
+	OnConflict *Upsert
+	// Progress is called after every committed batch with the number of inserted rows
+	Progress func(inserted, total int)
+}
+
+// BulkInsert inserts the rows with multi-row INSERT statements. Each statement binds at most
+// MaxVariables values including those of OnConflict, and each batch of BatchSize rows runs in
+// its own transaction, when db is already a transaction the batches run in it.
+// AUTOINCREMENT ids aren't set back to the rows.
+func BulkInsert[T any](ctx context.Context, db Executor, rows []T, opts BulkOptions) error {
+	var model T
+	if !isModel(model) {
+		panic(fmt.Errorf("Error BulkInsert of %s, it isn't a struct", reflect.TypeFor[T]()))
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 1000
+	}
+	if opts.MaxVariables <= 0 {
+		opts.MaxVariables = DEFAULT_MAX_VARIABLES
+	}
+
+	columns := len(INSERT_INTO(model).ColumnNames())
+	if columns == 0 {
+		panic(fmt.Errorf("Error BulkInsert of %s, it has no column to insert", reflect.TypeFor[T]()))
+	}
+	// Every statement binds the values of the upsert besides those of its rows
+	var upsertVariables int
+	if opts.OnConflict != nil {
+		_, args := opts.OnConflict.Build()
+		upsertVariables = len(args)
+	}
+	statementRows := (opts.MaxVariables - upsertVariables) / columns
+	if statementRows <= 0 {
+		return fmt.Errorf("bulk insert of %d columns and %d upsert variables exceeds the limit of %d variables", columns, upsertVariables, opts.MaxVariables)
+	}
+
+	for start := 0; start < len(rows); start += opts.BatchSize {
+		batch := rows[start:min(start+opts.BatchSize, len(rows))]
+		if err := insertBatch(ctx, db, model, batch, statementRows, opts); err != nil {
+			return err
+		}
+		if opts.Progress != nil {
+			opts.Progress(start+len(batch), len(rows))
+		}
+	}
+	return nil
+}
+
+// insertBatch inserts the batch in a transaction of its own, or in db when it can't begin one
+func insertBatch[T any](ctx context.Context, db Executor, model T, batch []T, statementRows int, opts BulkOptions) (err error) {
+	exec := db
+	if beginner, ok := db.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		tx, err := beginner.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+		exec = tx
+	}
+
+	for start := 0; start < len(batch); start += statementRows {
+		chunk := batch[start:min(start+statementRows, len(batch))]
+		values := make([]any, len(chunk))
+		for i := range chunk {
+			values[i] = &chunk[i]
+		}
+
+		insert := INSERT_INTO(model).VALUES_FROM(values...)
+		if opts.Or != NO_CONFLICT {
+			insert.OR(opts.Or)
+		}
+		if opts.OnConflict != nil {
+			insert.ON_CONFLICT(opts.OnConflict)
+		}
+
+		query, args := insert.Build()
+		if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type bulkReading struct {
+	Id       int64   `sqlofi:"PRIMARY KEY AUTOINCREMENT"`
+	Sensor   string  `sqlofi:"NOT NULL"`
+	Value    float64 `sqlofi:"NOT NULL"`
+	Recorded int64   `sqlofi:"NOT NULL"`
+}
+
+type bulkPair struct {
+	A int64 `sqlofi:"PRIMARY KEY"`
+	B int64 `sqlofi:"NOT NULL"`
+}
+
+func TestBulkInsertCountsUpsertVariables(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if _, err := db.ExecContext(ctx, CREATE_TABLE(bulkPair{}).Build()); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := make([]bulkPair, 20000)
+	for i := range rows {
+		rows[i] = bulkPair{A: int64(i), B: int64(i)}
+	}
+	// One statement fits all rows of a batch unless the upsert value is counted
+	opts := BulkOptions{
+		BatchSize:  len(rows),
+		OnConflict: UPSERT("A").DO_UPDATE(SET("B", Expr(int64(5)))),
+	}
+	if err := BulkInsert(ctx, db, rows, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := BulkInsert(ctx, db, rows, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := QueryOne[int64](ctx, db, SELECT(NOTHING, NewExpressionColumn(COUNT_ALL())).
+		FROM(NewTableFrom("bulk_pair")).
+		WHERE(EQ(Expr(NewColumnRef("bulk_pair", "B")), Expr(int64(5)))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated != int64(len(rows)) {
+		t.Fatalf("updated %d rows, want %d", updated, len(rows))
+	}
+}
+
+func TestBulkInsertTooManyVariables(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	opts := BulkOptions{
+		MaxVariables: 2,
+		OnConflict:   UPSERT("A").DO_UPDATE(SET("B", Expr(int64(5)))),
+	}
+	if err := BulkInsert(ctx, db, []bulkPair{{A: 1, B: 1}}, opts); err == nil {
+		t.Fatal("expected an error for 2 columns and 1 upsert variable with a limit of 2")
+	}
+}
+
+const benchmarkRows = 10000
+
+func benchmarkReadings() []bulkReading {
+	rows := make([]bulkReading, benchmarkRows)
+	for i := range rows {
+		rows[i] = bulkReading{
+			Sensor:   fmt.Sprintf("sensor-%d", i%50),
+			Value:    float64(i) * 0.25,
+			Recorded: int64(i),
+		}
+	}
+	return rows
+}
+
+// benchmarkInsert creates the table in a database file and times insert, the table is
+// emptied before every iteration
+func benchmarkInsert(b *testing.B, insert func(ctx context.Context, db *sql.DB, rows []bulkReading) error) {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, CREATE_TABLE(bulkReading{}).Build()); err != nil {
+		b.Fatal(err)
+	}
+
+	rows := benchmarkReadings()
+	clear, _ := DELETE_FROM(bulkReading{}).All().Build()
+
+	b.ResetTimer()
+	for range b.N {
+		b.StopTimer()
+		if _, err := db.ExecContext(ctx, clear); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		if err := insert(ctx, db, rows); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(rows))/b.Elapsed().Seconds(), "rows/s")
+}
+
+func BenchmarkBulkInsert(b *testing.B) {
+	benchmarkInsert(b, func(ctx context.Context, db *sql.DB, rows []bulkReading) error {
+		return BulkInsert(ctx, db, rows, BulkOptions{})
+	})
+}
+
+func BenchmarkInsertPerRow(b *testing.B) {
+	benchmarkInsert(b, func(ctx context.Context, db *sql.DB, rows []bulkReading) error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		repo := NewRepo[bulkReading](tx)
+		for i := range rows {
+			row := rows[i]
+			if err := repo.Insert(ctx, &row); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"fmt"
+
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	foreignkey "github.com/Nevoral/sqlofi/internal/sqlite/ForeignKey"
+	generated "github.com/Nevoral/sqlofi/internal/sqlite/Generated"
+	sortorder "github.com/Nevoral/sqlofi/internal/sqlite/SortOrder"
+	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
+	types "github.com/Nevoral/sqlofi/internal/sqlite/Types"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// SQLiteType is the declared type of a column
+type SQLiteType = types.SQLiteType
+
+const (
+	INTEGER SQLiteType = types.INTEGER
+	REAL    SQLiteType = types.REAL
+	TEXT    SQLiteType = types.TEXT
+	BLOB    SQLiteType = types.BLOB
+)
+
+// StorageType is the storage of a generated column
+type StorageType string
+
+const (
+	NO_STORAGE StorageType = ""
+	STORED     StorageType = "STORED"
+	VIRTUAL    StorageType = "VIRTUAL"
+)
+
+func (s StorageType) String() string {
+	return string(s)
+}
+
+// NewDynamicTable creates a table without a Go struct, e.g. for tables defined at runtime.
+// The columns are added with Column and the table works like one created by CREATE_TABLE.
+func NewDynamicTable(name string) *Table {
+	return &Table{
+		Table: table.NewDynamicTable(name),
+	}
+}
+
+// Column appends columns to the table, for tables created from a model
+// they come after the columns of the struct fields
+func (t *Table) Column(columns ...*Column) *Table {
+	for _, col := range columns {
+		t.Table.Column(col.Column)
+	}
+	return t
+}
+
+// Col creates a column definition for tables built without a Go struct.
+// The name is converted to snake_case like the names of struct fields, so
+// indexes, foreign keys and the statement builders refer to it by the same name.
+func Col(name string, colType SQLiteType) *Column {
+	return &Column{
+		Column: column.NewColumn(utils.ToSnakeCase(name), colType),
+	}
+}
+
+type Column struct {
+	*column.Column
+}
+
+// PrimaryKey adds a PRIMARY KEY constraint to the column.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) PrimaryKey(constraintName string, direction OrderDirection, conflict ConflictClause, autoincrement bool) *Column {
+	c.Column.PrimaryKey(constraintName, sortorder.SortOrder(direction), conflict.String(), autoincrement)
+	return c
+}
+
+// NotNull adds a NOT NULL constraint to the column.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) NotNull(constraintName string, conflict ConflictClause) *Column {
+	c.Column.NotNull(constraintName, conflict.String())
+	return c
+}
+
+// Unique adds a UNIQUE constraint to the column.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) Unique(constraintName string, conflict ConflictClause) *Column {
+	c.Column.Unique(constraintName, conflict.String())
+	return c
+}
+
+// Check adds a CHECK constraint to the column.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) Check(constraintName string, expression *Expression) *Column {
+	c.Column.Check(constraintName, expression.Expression)
+	return c
+}
+
+// Default adds a DEFAULT constraint to the column, value is written as in the tag:
+// a literal, a signed number or a parenthesized expression.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) Default(constraintName string, value string) *Column {
+	c.Column.Default(constraintName, value)
+	return c
+}
+
+// Collate adds a COLLATE constraint to the column.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) Collate(constraintName string, collation string) *Column {
+	c.Column.Collate(constraintName, collation)
+	return c
+}
+
+// References adds a REFERENCES constraint to the column, key is built with REFERENCES.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) References(constraintName string, key *ForeignKey) *Column {
+	c.Column.References(constraintName, key.References)
+	return c
+}
+
+// Generated makes the column a generated column computed from the expression.
+// constaintName is the name of the constraint if "" it would be without named constraint.
+func (c *Column) Generated(constraintName string, expression *Expression, storage StorageType) *Column {
+	c.Column.Generated(constraintName, true, expression.Expression, generated.StorageType(storage))
+	return c
+}
+
+// Index adds the column to the index, columns sharing the index name make up one index
+func (c *Column) Index(indexName string, unique bool, direction OrderDirection) *Column {
+	c.Column.Index(indexName, unique, sortorder.SortOrder(direction))
+	return c
+}
+
+// REFERENCES creates the foreign key clause of a column constraint,
+// foreignTable is a model or a table created by NewDynamicTable, the columns are
+// field names or ColumnRefs.
+func REFERENCES[C ColumnName](foreignTable any, foreignColumns ...C) *ForeignKey {
+	if foreignTable == nil {
+		panic(fmt.Errorf("Error no Table provided"))
+	}
+	return &ForeignKey{
+		References: foreignkey.NewColumnReferences("", foreignTable, columnFields(foreignColumns)),
+	}
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"fmt"
+	"reflect"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// NewColumnRef references the column of a model field. It's used by the column
+// descriptors written by "sqlofi columns", e.g. UserCols.Email, so a renamed field
+// fails to compile instead of panicking at runtime.
+func NewColumnRef(table string, field string) ColumnRef {
+	return ColumnRef{
+		table: table,
+		field: field,
+	}
+}
+
+// ColumnRef is a typed reference to a column of a table
+type ColumnRef struct {
+	table string
+	field string
+}
+
+// Table returns the SQL name of the table
+func (c ColumnRef) Table() string {
+	return c.table
+}
+
+// Field returns the Go field name of the column, for the builders taking a field name
+func (c ColumnRef) Field() string {
+	return c.field
+}
+
+// Name returns the SQL name of the column
+func (c ColumnRef) Name() string {
+	return utils.ToSnakeCase(c.field)
+}
+
+// String returns the qualified column, table.column
+func (c ColumnRef) String() string {
+	if c.table == "" {
+		return c.Name()
+	}
+	return fmt.Sprintf("%s.%s", c.table, c.Name())
+}
+
+// ColumnName is a column given either by its Go field name or by a ColumnRef
+type ColumnName interface {
+	~string | ColumnRef
+}
+
+// columnField returns the Go field name of the column
+func columnField[C ColumnName](column C) string {
+	if ref, ok := any(column).(ColumnRef); ok {
+		return ref.field
+	}
+	return reflect.ValueOf(column).String()
+}
+
+func columnFields[C ColumnName](columns []C) []string {
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i] = columnField(column)
+	}
+	return fields
+}
+
+// ColOf references the column of the field of the model T, e.g. ColOf[User]("Email").
+// The table and column names follow the naming of CREATE_TABLE, it panics when
+// the field isn't a column of the model.
+func ColOf[T any](field string) ColumnRef {
+	modelType := reflect.TypeFor[T]()
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	model := reflect.New(modelType).Elem().Interface()
+	for _, f := range reflectutil.GetStructFields(model) {
+		if f.Name != field {
+			continue
+		}
+		if tag, ok := f.Tag.Lookup("sqlofi"); !ok || tag == "-" {
+			panic(fmt.Errorf("Error field %s of %s isn't a column, it has no sqlofi tag", field, reflectutil.GetStructName(model)))
+		}
+		return NewColumnRef(reflectutil.GetTableName(model), field)
+	}
+	panic(fmt.Errorf("Error field %s isn't present in %s", field, reflectutil.GetStructName(model)))
+}
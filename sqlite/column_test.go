@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection of :memory: is a database of its own
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestDynamicTableCamelCaseColumn(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	events := NewDynamicTable("event").Column(
+		Col("id", INTEGER).PrimaryKey("", ASC, NO_CONFLICT, true),
+		Col("userName", TEXT).NotNull("", NO_CONFLICT),
+	)
+	if got, want := events.ColumnNames()[1], "user_name"; got != want {
+		t.Fatalf("column name = %s, want %s", got, want)
+	}
+
+	statements := []string{
+		events.Build(),
+		CREATE_INDEX(events, "event_user", NewIndexedColumn("userName")).Build(),
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+
+	insert, args := INSERT_INTO(events).COLUMNS("userName").VALUES(Expr("ada")).Build()
+	if _, err := db.ExecContext(ctx, insert, args...); err != nil {
+		t.Fatalf("%s: %v", insert, err)
+	}
+
+	name := NewColumnRef("event", "userName")
+	names, err := Query[string](ctx, db, SELECT(NOTHING, NewExpressionColumn(Expr(name))).
+		FROM(NewTableFrom("event")).
+		WHERE(EQ(Expr(name), Expr("ada"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "ada" {
+		t.Fatalf("names = %v, want [ada]", names)
+	}
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	deletestmt "github.com/Nevoral/sqlofi/internal/sqlite/Delete"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+)
+
+// DELETE_FROM creates a DELETE from the table of the model, or of the table named by a string.
+// Build panics when there is no WHERE, All has to be called to delete every row.
+func DELETE_FROM(model any) *Delete {
+	return &Delete{
+		Delete: deletestmt.NewDelete(reflectutil.GetTableName(model)),
+	}
+}
+
+// DELETE_FROM creates a DELETE prefixed with the WITH clause
+func (w *With) DELETE_FROM(model any) *Delete {
+	d := DELETE_FROM(model)
+	d.Delete.With(w.With)
+	return d
+}
+
+// Delete is a DELETE statement, Build returns its SQL and the bound values
+type Delete struct {
+	*deletestmt.Delete
+}
+
+// AS sets an alias for the table
+func (d *Delete) AS(alias string) *Delete {
+	d.Delete.Alias(alias)
+	return d
+}
+
+// WHERE sets the condition of the deleted rows
+func (d *Delete) WHERE(condition *Expression) *Delete {
+	d.Delete.Where(condition.Expression)
+	return d
+}
+
+// All confirms the DELETE without WHERE is meant to delete every row
+func (d *Delete) All() *Delete {
+	d.Delete.All()
+	return d
+}
+
+// RETURNING sets the columns returned for every deleted row
+func (d *Delete) RETURNING(columns ...*ResultColumn) *Delete {
+	d.Delete.Returning(resultColumns(columns)...)
+	return d
+}
+
+// ORDER_BY sets the order in which LIMIT picks the rows, it needs SQLite built
+// with SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+func (d *Delete) ORDER_BY(orderBy ...*OrderBy) *Delete {
+	orders := make([]*selectstmt.OrderBy, len(orderBy))
+	for i, order := range orderBy {
+		orders[i] = order.OrderBy
+	}
+	d.Delete.OrderBy(orders...)
+	return d
+}
+
+// LIMIT limits the number of deleted rows, it needs SQLite built with
+// SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+func (d *Delete) LIMIT(limit int) *Delete {
+	d.Delete.Limit(limit)
+	return d
+}
+
+// OFFSET skips rows before LIMIT
+func (d *Delete) OFFSET(offset int) *Delete {
+	d.Delete.Offset(offset)
+	return d
+}
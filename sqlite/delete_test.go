@@ -0,0 +1,53 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPanic returns the message of the panic of build, "" when it doesn't panic
+func buildPanic(build func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	build()
+	return ""
+}
+
+func TestDeleteSafeMode(t *testing.T) {
+	newest := NewOrderBy(Expr(ColOf[insertUser]("Id")), DESC)
+	tests := []struct {
+		name   string
+		delete *Delete
+		want   string
+		panics string
+	}{
+		{"without WHERE", DELETE_FROM(insertUser{}), "", "without WHERE"},
+		{"WHERE", DELETE_FROM(insertUser{}).WHERE(EQ(Expr(ColOf[insertUser]("Id")), Expr(1))), "DELETE FROM insert_user WHERE insert_user.id = ?", ""},
+		{"All", DELETE_FROM(insertUser{}).All(), "DELETE FROM insert_user", ""},
+		{"ORDER BY without LIMIT", DELETE_FROM(insertUser{}).All().ORDER_BY(newest), "", "without LIMIT"},
+		{"OFFSET without LIMIT", DELETE_FROM(insertUser{}).All().OFFSET(10), "", "without LIMIT"},
+		{"ORDER BY with LIMIT", DELETE_FROM(insertUser{}).All().ORDER_BY(newest).LIMIT(5), "DELETE FROM insert_user ORDER BY insert_user.id DESC LIMIT 5", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			msg := buildPanic(func() { query, _ = tt.delete.Build() })
+			if tt.panics != "" {
+				if !strings.Contains(msg, tt.panics) {
+					t.Fatalf("panic = %q, want one containing %q", msg, tt.panics)
+				}
+				return
+			}
+			if msg != "" {
+				t.Fatalf("unexpected panic: %s", msg)
+			}
+			if query != tt.want {
+				t.Errorf("query = %q, want %q", query, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package sqlite
+
+import (
+	docs "github.com/Nevoral/sqlofi/internal/sqlite/Docs"
+)
+
+// DocFormat is the output language of Schema.Docs
+type DocFormat = docs.Format
+
+const (
+	MARKDOWN DocFormat = docs.MARKDOWN
+	HTML     DocFormat = docs.HTML
+)
+
+// Docs generates the reference documentation of the schema tables, the data dictionary.
+// Every table lists its columns with type, constraints, default and generated expression
+// next to its keys, foreign keys, checks, indexes and triggers.
+// The doc comments of the models and their fields describe the tables and columns,
+// they are read from sourceDirs or, when none are given, from the source of each model package.
+func (s *Schema) Docs(format DocFormat, sourceDirs ...string) (string, error) {
+	comments := docs.Comments{}
+	if len(sourceDirs) > 0 {
+		if err := comments.LoadComments(sourceDirs...); err != nil {
+			return "", err
+		}
+	} else {
+		for _, t := range s.tables {
+			if err := comments.LoadModelComments(t.Model()); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return docs.NewDocument(s.name, s.tables, s.enums, s.indexes, s.triggers, comments).Build(format), nil
+}
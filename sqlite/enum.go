@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"reflect"
+
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
+)
+
+// Enumerator is implemented by types with a fixed set of allowed values.
+// Every column of such a type gets a CHECK (column IN (...)) constraint.
+// The method has to return a slice of the type itself, e.g.
+//
+//	func (OrderStatus) Enum() []OrderStatus
+type Enumerator[T any] interface {
+	Enum() []T
+}
+
+// NewEnum registers the allowed values of the type T for use with Schema.Enum.
+// It is an alternative to the Enum() method for types that can't declare it.
+func NewEnum[T any](values ...T) *Enum {
+	return &Enum{
+		typ:    reflect.TypeFor[T](),
+		values: enum.Literals(reflect.ValueOf(values)),
+	}
+}
+
+// Enum holds the allowed values of an enum type
+type Enum struct {
+	typ    reflect.Type
+	values []string
+}
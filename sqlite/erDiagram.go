@@ -0,0 +1,21 @@
+package sqlite
+
+import (
+	erdiagram "github.com/Nevoral/sqlofi/internal/sqlite/ERDiagram"
+)
+
+// DiagramFormat is the output language of Schema.ERDiagram
+type DiagramFormat = erdiagram.Format
+
+const (
+	MERMAID  DiagramFormat = erdiagram.MERMAID
+	GRAPHVIZ DiagramFormat = erdiagram.GRAPHVIZ
+)
+
+// ERDiagram draws the entity-relationship diagram of the schema tables as Mermaid erDiagram or Graphviz DOT.
+// Columns show their type and PK, FK and UK markers, foreign keys become relationships labeled with
+// their columns and ON DELETE action. The referenced side is optional when the foreign key is nullable
+// and the referencing side holds at most one row when the foreign key is UNIQUE.
+func (s *Schema) ERDiagram(format DiagramFormat) string {
+	return erdiagram.NewDiagram(s.tables).Build(format)
+}
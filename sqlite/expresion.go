@@ -1,193 +1,363 @@
 package sqlite
 
 import (
-	"fmt"
-	"strings"
+	"time"
 
 	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
-	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
 	types "github.com/Nevoral/sqlofi/internal/sqlite/Types"
 )
 
-func Expr[T types.LiteralValue | types.DbPath | types.BindingParameter | string | int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | []byte | float32 | float64 | bool](expression T) *Expression {
+// BindingParameter is the placeholder of a bound value, "?", "?NNN", ":name", "@name" or "$name"
+type BindingParameter = types.BindingParameter
+
+// BindingType is the kind of a BindingParameter
+type BindingType = types.BindingType
+
+const (
+	AUTOINCREMENTED BindingType = types.AUTOINCREMENTED // ?
+	INDEXED         BindingType = types.INDEXED         // ?NNN
+	COLON_NAMED     BindingType = types.COLON_NAMED     // :name
+	AT_NAMED        BindingType = types.AT_NAMED        // @name
+	DOLAR_NAMED     BindingType = types.DOLAR_NAMED     // $name
+)
+
+// NewBindingParameter creates the placeholder of the kind, value is the name or the index
+func NewBindingParameter(b BindingType, value string) BindingParameter {
+	return types.NewBindingParameter(b, value)
+}
+
+// Value is a Go value usable in an expression
+type Value interface {
+	string | int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | []byte | float32 | float64 | bool | time.Time
+}
+
+// Expr creates an expression from a value. Go values are bound as "?" parameters and returned
+// by Build as arguments, a ColumnRef or DbPath is a column reference and a LiteralValue or
+// BindingParameter is written as is, the value of such a parameter is passed by the caller.
+func Expr[T types.LiteralValue | types.DbPath | ColumnRef | types.BindingParameter | Value](expression T) *Expression {
 	switch ex := any(expression).(type) {
 	case types.LiteralValue:
-		return &Expression{Expression: expr.NewExpression(ex.String())}
+		return NewExpression(ex.String())
 	case types.DbPath:
-		return &Expression{Expression: expr.NewExpression(ex.StringColumn())}
+		return NewExpression(ex.StringColumn())
+	case ColumnRef:
+		return newExpression(&expr.Column{Table: ex.Table(), Name: ex.Name()})
 	case types.BindingParameter:
-		return &Expression{Expression: expr.NewExpression(ex.String())}
-	case string:
-		return &Expression{Expression: expr.NewExpression("\"" + ex + "\"")}
-	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return &Expression{Expression: expr.NewExpression(fmt.Sprintf("%d", ex))}
-	case []byte:
-		return &Expression{Expression: expr.NewExpression("\"" + string(ex) + "\"")}
-	case float32, float64:
-		return &Expression{Expression: expr.NewExpression(fmt.Sprintf("%f", ex))}
-	case bool:
-		if ex {
-			return &Expression{Expression: expr.NewExpression("TRUE")}
-		}
-		return &Expression{Expression: expr.NewExpression("FALSE")}
+		return NewExpression(ex.String())
 	}
-	return &Expression{Expression: expr.NewExpression("")}
+	return newExpression(&expr.Param{Param: "?", Value: expression})
+}
+
+// Param binds the value to the named or numbered parameter, e.g.
+// Param(NewBindingParameter(COLON_NAMED, "id"), 42) is written as :id and
+// returned by Build as sql.Named("id", 42)
+func Param[T Value](param BindingParameter, value T) *Expression {
+	return newExpression(&expr.Param{Param: param.String(), Value: value})
+}
+
+// Literal writes the value into the SQL instead of binding it, strings are quoted
+// with their quotes escaped and []byte is written as a blob literal
+func Literal[T Value](value T) *Expression {
+	return newExpression(&expr.Literal{Value: value})
+}
+
+// NewExpression creates an expression from raw SQL, it's written as is and
+// parenthesized when it's the operand of an operator
+func NewExpression(sql string) *Expression {
+	return &Expression{Expression: expr.NewExpression(sql)}
 }
 
+func newExpression(node expr.Node) *Expression {
+	return &Expression{Expression: expr.NewNodeExpression(node)}
+}
+
+// Expression is a node of an expression tree, the functions combining expressions
+// parenthesize their operands by the SQLite operator precedence
 type Expression struct {
 	*expr.Expression
 }
 
-func Expressions(expressions ...*Expression) string {
-	var expr string
-	for idx, expression := range expressions {
-		if expression == nil {
-			panic("nil expression")
-		}
-		if idx > 0 {
-			expr += ", "
-		}
-		expr += expression.Build()
+// nodes returns the nodes of the expressions, it panics on a nil expression
+func nodes(expressions []*Expression) []expr.Node {
+	result := make([]expr.Node, len(expressions))
+	for i, expression := range expressions {
+		result[i] = node(expression)
 	}
-	return fmt.Sprintf("(%s)", expr)
+	return result
 }
 
-func CAST(expression *Expression, typeName types.SQLiteType) string {
-	return fmt.Sprintf("CAST(%s AS %s)", expression.Build(), typeName)
+func node(expression *Expression) expr.Node {
+	if expression == nil || expression.Expression == nil {
+		panic("nil expression")
+	}
+	return expression.Expression
 }
 
-func COLLATE(expression *Expression, collation string) string {
-	return fmt.Sprintf("%s COLLATE %s", expression.Build(), collation)
+func binary(operator string, prec expr.Precedence, left, right *Expression) *Expression {
+	return newExpression(&expr.Binary{Operator: operator, Left: node(left), Right: node(right), Prec: prec})
 }
 
-func NOT_LIKE(firstExpr *Expression, likeExpr *Expression, escapeExpr *Expression) string {
-	if escapeExpr == nil {
-		return fmt.Sprintf("%s NOT LIKE %s", firstExpr.Build(), likeExpr.Build())
+func chain(operator string, prec expr.Precedence, expressions []*Expression) *Expression {
+	if len(expressions) == 0 {
+		panic(operator + " requires at least one expression")
 	}
-	return fmt.Sprintf("%s NOT LIKE %s ESCAPE %s", firstExpr.Build(), likeExpr.Build(), escapeExpr.Build())
+	return newExpression(&expr.Chain{Operator: operator, Operands: nodes(expressions), Prec: prec})
+}
+
+// Expressions creates a row value, (a, b, ...)
+func Expressions(expressions ...*Expression) *Expression {
+	return newExpression(&expr.Row{Values: nodes(expressions)})
+}
+
+func EQ(left, right *Expression) *Expression {
+	return binary("=", expr.PREC_EQUALITY, left, right)
+}
+
+func NE(left, right *Expression) *Expression {
+	return binary("<>", expr.PREC_EQUALITY, left, right)
+}
+
+func LT(left, right *Expression) *Expression {
+	return binary("<", expr.PREC_COMPARISON, left, right)
+}
+
+func LE(left, right *Expression) *Expression {
+	return binary("<=", expr.PREC_COMPARISON, left, right)
+}
+
+func GT(left, right *Expression) *Expression {
+	return binary(">", expr.PREC_COMPARISON, left, right)
+}
+
+func GE(left, right *Expression) *Expression {
+	return binary(">=", expr.PREC_COMPARISON, left, right)
+}
+
+// AND joins the conditions, a single condition is returned as is
+func AND(conditions ...*Expression) *Expression {
+	return chain("AND", expr.PREC_AND, conditions)
+}
+
+// OR joins the conditions, a single condition is returned as is
+func OR(conditions ...*Expression) *Expression {
+	return chain("OR", expr.PREC_OR, conditions)
+}
+
+func NOT(condition *Expression) *Expression {
+	return newExpression(&expr.Unary{Operator: "NOT", Operand: node(condition), Prec: expr.PREC_NOT})
+}
+
+// CONCAT joins the strings with the || operator
+func CONCAT(expressions ...*Expression) *Expression {
+	return chain("||", expr.PREC_CONCAT, expressions)
+}
+
+func ADD(left, right *Expression) *Expression {
+	return binary("+", expr.PREC_ADDITIVE, left, right)
+}
+
+func SUB(left, right *Expression) *Expression {
+	return binary("-", expr.PREC_ADDITIVE, left, right)
 }
 
-func LIKE(firstExpr *Expression, likeExpr *Expression, escapeExpr *Expression) string {
-	if escapeExpr == nil {
-		return fmt.Sprintf("%s LIKE %s", firstExpr.Build(), likeExpr.Build())
+func MUL(left, right *Expression) *Expression {
+	return binary("*", expr.PREC_MULTIPLY, left, right)
+}
+
+func DIV(left, right *Expression) *Expression {
+	return binary("/", expr.PREC_MULTIPLY, left, right)
+}
+
+func MOD(left, right *Expression) *Expression {
+	return binary("%", expr.PREC_MULTIPLY, left, right)
+}
+
+func NEG(expression *Expression) *Expression {
+	return newExpression(&expr.Unary{Operator: "-", Operand: node(expression), Prec: expr.PREC_UNARY})
+}
+
+func BIT_AND(left, right *Expression) *Expression {
+	return binary("&", expr.PREC_BITWISE, left, right)
+}
+
+func BIT_OR(left, right *Expression) *Expression {
+	return binary("|", expr.PREC_BITWISE, left, right)
+}
+
+func BIT_NOT(expression *Expression) *Expression {
+	return newExpression(&expr.Unary{Operator: "~", Operand: node(expression), Prec: expr.PREC_UNARY})
+}
+
+func SHIFT_LEFT(left, right *Expression) *Expression {
+	return binary("<<", expr.PREC_BITWISE, left, right)
+}
+
+func SHIFT_RIGHT(left, right *Expression) *Expression {
+	return binary(">>", expr.PREC_BITWISE, left, right)
+}
+
+// FUNC calls the SQL function with the arguments, e.g. FUNC("lower", Expr(UserCols.Email))
+func FUNC(name string, args ...*Expression) *Expression {
+	return newExpression(&expr.Function{Name: name, Args: nodes(args)})
+}
+
+// FUNC_DISTINCT calls the aggregate function on the distinct values, e.g. count(DISTINCT x)
+func FUNC_DISTINCT(name string, args ...*Expression) *Expression {
+	return newExpression(&expr.Function{Name: name, Distinct: true, Args: nodes(args)})
+}
+
+// COUNT_ALL counts the rows, count(*)
+func COUNT_ALL() *Expression {
+	return newExpression(&expr.Function{Name: "count", Star: true})
+}
+
+func CAST(expression *Expression, typeName types.SQLiteType) *Expression {
+	return newExpression(&expr.Cast{Operand: node(expression), Type: typeName.String()})
+}
+
+func COLLATE(expression *Expression, collation string) *Expression {
+	return newExpression(&expr.Collate{Operand: node(expression), Collation: collation})
+}
+
+func pattern(operator string, not bool, left, right, escape *Expression) *Expression {
+	p := &expr.Pattern{Operator: operator, Not: not, Left: node(left), Right: node(right)}
+	if escape != nil {
+		p.Escape = node(escape)
 	}
-	return fmt.Sprintf("%s LIKE %s ESCAPE %s", firstExpr.Build(), likeExpr.Build(), escapeExpr.Build())
+	return newExpression(p)
+}
+
+// NOT_LIKE matches the pattern, escapeExpr is optional
+func NOT_LIKE(firstExpr *Expression, likeExpr *Expression, escapeExpr *Expression) *Expression {
+	return pattern("LIKE", true, firstExpr, likeExpr, escapeExpr)
+}
+
+// LIKE matches the pattern, escapeExpr is optional
+func LIKE(firstExpr *Expression, likeExpr *Expression, escapeExpr *Expression) *Expression {
+	return pattern("LIKE", false, firstExpr, likeExpr, escapeExpr)
 }
 
-func NOT_GLOB(firstExpr *Expression, globExpr *Expression) string {
-	return fmt.Sprintf("%s NOT GLOB %s", firstExpr.Build(), globExpr.Build())
+func NOT_GLOB(firstExpr *Expression, globExpr *Expression) *Expression {
+	return pattern("GLOB", true, firstExpr, globExpr, nil)
 }
 
-func GLOB(firstExpr *Expression, globExpr *Expression) string {
-	return fmt.Sprintf("%s GLOB %s", firstExpr.Build(), globExpr.Build())
+func GLOB(firstExpr *Expression, globExpr *Expression) *Expression {
+	return pattern("GLOB", false, firstExpr, globExpr, nil)
 }
 
-func NOT_REGEXP(firstExpr *Expression, regexpExpr *Expression) string {
-	return fmt.Sprintf("%s NOT REGEXP %s", firstExpr.Build(), regexpExpr.Build())
+func NOT_REGEXP(firstExpr *Expression, regexpExpr *Expression) *Expression {
+	return pattern("REGEXP", true, firstExpr, regexpExpr, nil)
 }
 
-func REGEXP(firstExpr *Expression, regexpExpr *Expression) string {
-	return fmt.Sprintf("%s REGEXP %s", firstExpr.Build(), regexpExpr.Build())
+func REGEXP(firstExpr *Expression, regexpExpr *Expression) *Expression {
+	return pattern("REGEXP", false, firstExpr, regexpExpr, nil)
 }
 
-func NOT_MATCH(firstExpr *Expression, matchExpr *Expression) string {
-	return fmt.Sprintf("%s NOT MATCH %s", firstExpr.Build(), matchExpr.Build())
+func NOT_MATCH(firstExpr *Expression, matchExpr *Expression) *Expression {
+	return pattern("MATCH", true, firstExpr, matchExpr, nil)
 }
 
-func MATCH(firstExpr *Expression, matchExpr *Expression) string {
-	return fmt.Sprintf("%s MATCH %s", firstExpr.Build(), matchExpr.Build())
+func MATCH(firstExpr *Expression, matchExpr *Expression) *Expression {
+	return pattern("MATCH", false, firstExpr, matchExpr, nil)
 }
 
-func ISNULL(expression *Expression) string {
-	return fmt.Sprintf("%s ISNULL", expression.Build())
+func postfix(operator string, expression *Expression) *Expression {
+	return newExpression(&expr.Unary{Operator: operator, Operand: node(expression), Postfix: true, Prec: expr.PREC_EQUALITY})
 }
 
-func NOTNULL(expression *Expression) string {
-	return fmt.Sprintf("%s NOTNULL", expression.Build())
+func ISNULL(expression *Expression) *Expression {
+	return postfix("ISNULL", expression)
 }
 
-func NOT_NULL(expression *Expression) string {
-	return fmt.Sprintf("%s NOT NULL", expression.Build())
+func NOTNULL(expression *Expression) *Expression {
+	return postfix("NOTNULL", expression)
 }
 
-func IS_NOT_DISTINCT_FROM(firstExpr, secondExpr *Expression) string {
-	return fmt.Sprintf("%s IS NOT DISTINCT FROM %s", firstExpr.Build(), secondExpr.Build())
+func NOT_NULL(expression *Expression) *Expression {
+	return postfix("NOT NULL", expression)
 }
 
-func IS_DISTINCT_FROM(firstExpr, secondExpr *Expression) string {
-	return fmt.Sprintf("%s IS DISTINCT FROM %s", firstExpr.Build(), secondExpr.Build())
+func IS_NOT_DISTINCT_FROM(firstExpr, secondExpr *Expression) *Expression {
+	return binary("IS NOT DISTINCT FROM", expr.PREC_EQUALITY, firstExpr, secondExpr)
 }
 
-func IS_NOT(firstExpr, secondExpr *Expression) string {
-	return fmt.Sprintf("%s IS NOT %s", firstExpr.Build(), secondExpr.Build())
+func IS_DISTINCT_FROM(firstExpr, secondExpr *Expression) *Expression {
+	return binary("IS DISTINCT FROM", expr.PREC_EQUALITY, firstExpr, secondExpr)
 }
 
-func IS(firstExpr, secondExpr *Expression) string {
-	return fmt.Sprintf("%s IS %s", firstExpr.Build(), secondExpr.Build())
+func IS_NOT(firstExpr, secondExpr *Expression) *Expression {
+	return binary("IS NOT", expr.PREC_EQUALITY, firstExpr, secondExpr)
 }
 
-func NOT_BETWEEN(firstExpr, betweenExpr, andExpr *Expression) string {
-	return fmt.Sprintf("%s NOT BETWEEN %s AND %s", firstExpr.Build(), betweenExpr.Build(), andExpr.Build())
+func IS(firstExpr, secondExpr *Expression) *Expression {
+	return binary("IS", expr.PREC_EQUALITY, firstExpr, secondExpr)
 }
 
-func BETWEEN(firstExpr, betweenExpr, andExpr *Expression) string {
-	return fmt.Sprintf("%s BETWEEN %s AND %s", firstExpr.Build(), betweenExpr.Build(), andExpr.Build())
+func NOT_BETWEEN(firstExpr, betweenExpr, andExpr *Expression) *Expression {
+	return newExpression(&expr.Between{Not: true, Operand: node(firstExpr), Low: node(betweenExpr), High: node(andExpr)})
 }
 
-// NOT_IN expression is not finished because of the complexity so be careful to use it
-func NOT_IN(firstExpr *Expression, body string) string {
-	return fmt.Sprintf("%s NOT IN %s", firstExpr.Build(), body)
+func BETWEEN(firstExpr, betweenExpr, andExpr *Expression) *Expression {
+	return newExpression(&expr.Between{Operand: node(firstExpr), Low: node(betweenExpr), High: node(andExpr)})
 }
 
-// NOT_IN expression is not finished because of the complexity so be careful to use it
-func IN(firstExpr *Expression, body string) string {
-	return fmt.Sprintf("%s IN %s", firstExpr.Build(), body)
+// NOT_IN checks the expression isn't one of the values
+func NOT_IN(firstExpr *Expression, values ...*Expression) *Expression {
+	return newExpression(&expr.In{Not: true, Operand: node(firstExpr), Values: nodes(values)})
 }
 
-func NOT_EXISTS(sel *selectstmt.Select) string {
-	return fmt.Sprintf("NOT EXISTS(%s)", sel.Build())
+// IN checks the expression is one of the values
+func IN(firstExpr *Expression, values ...*Expression) *Expression {
+	return newExpression(&expr.In{Operand: node(firstExpr), Values: nodes(values)})
 }
 
-func EXISTS(sel *Select) string {
-	return fmt.Sprintf("EXISTS(%s)", sel.Build())
+// NOT_IN_SELECT checks the expression isn't in the result of the subquery
+func NOT_IN_SELECT(firstExpr *Expression, sel *Select) *Expression {
+	return newExpression(&expr.In{Not: true, Operand: node(firstExpr), Subquery: sel})
 }
 
-func SELECTexpr(sel *Select) string {
-	return fmt.Sprintf("(%s)", sel.Build())
+// IN_SELECT checks the expression is in the result of the subquery
+func IN_SELECT(firstExpr *Expression, sel *Select) *Expression {
+	return newExpression(&expr.In{Operand: node(firstExpr), Subquery: sel})
 }
 
-func CASE(expr *Expression, whenExpr []*Expression, thenExpr []*Expression, elseExpr *Expression) string {
-	var (
-		whenThen = make([]string, len(whenExpr))
-		elseStr  string
-		exprStr  string
-	)
+func NOT_EXISTS(sel *Select) *Expression {
+	return newExpression(&expr.Exists{Not: true, Subquery: sel})
+}
+
+func EXISTS(sel *Select) *Expression {
+	return newExpression(&expr.Exists{Subquery: sel})
+}
+
+// SELECTexpr uses the result of the subquery as a value
+func SELECTexpr(sel *Select) *Expression {
+	return newExpression(&expr.Subquery{Statement: sel})
+}
+
+// CASE creates a CASE expression, caseExpr and elseExpr are optional
+func CASE(caseExpr *Expression, whenExpr []*Expression, thenExpr []*Expression, elseExpr *Expression) *Expression {
 	if len(whenExpr) != len(thenExpr) {
 		panic("CASE statement requires an equal number of WHEN and THEN clauses")
 	}
 
-	if expr != nil {
-		exprStr = fmt.Sprintf(" %s", expr.Build())
-	}
-
-	for i := range whenExpr {
-		whenThen[i] = fmt.Sprintf("WHEN %s THEN %s", whenExpr[i].Build(), thenExpr[i].Build())
+	c := &expr.Case{When: nodes(whenExpr), Then: nodes(thenExpr)}
+	if caseExpr != nil {
+		c.Operand = node(caseExpr)
 	}
-
 	if elseExpr != nil {
-		elseStr = fmt.Sprintf(" ELSE %s", elseExpr.Build())
+		c.Else = node(elseExpr)
 	}
-
-	return fmt.Sprintf("CASE%s %s%s END", exprStr, strings.Join(whenThen, " "), elseStr)
+	return newExpression(c)
 }
 
-func RAISE(action ConflictClause, expr *Expression) string {
+// RAISE raises an error in a trigger, message is unused with IGNORE
+func RAISE(action ConflictClause, message *Expression) *Expression {
 	if action == REPLACE || action == NO_CONFLICT {
 		panic("RAISE statement cannot be used with REPLACE or empty string")
 	}
 
 	if action == IGNORE {
-		return fmt.Sprintf("RAISE(%s)", action)
+		return newExpression(&expr.Raise{Action: action.String()})
 	}
-	return fmt.Sprintf("RAISE(%s, %s)", action, expr.Build())
+	return newExpression(&expr.Raise{Action: action.String(), Message: node(message)})
 }
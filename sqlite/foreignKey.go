@@ -32,12 +32,13 @@ func (d DeferrableAction) String() string {
 	return string(d)
 }
 
-func FOREIGN_KEY(foreignTablePtr any, columns ...string) *ForeignKey {
+// FOREIGN_KEY references the columns of the foreign table, given as field names or ColumnRefs
+func FOREIGN_KEY[C ColumnName](foreignTablePtr any, columns ...C) *ForeignKey {
 	if foreignTablePtr == nil {
 		panic(fmt.Errorf("Error no Table provided"))
 	}
 	return &ForeignKey{
-		References: foreignkey.NewTableForeignTable(foreignTablePtr, columns),
+		References: foreignkey.NewTableForeignTable(foreignTablePtr, columnFields(columns)),
 	}
 }
 
@@ -1,21 +1,27 @@
 package sqlite
 
 import (
+	"reflect"
+
 	idxcol "github.com/Nevoral/sqlofi/internal/sqlite/IndexedColumn"
 )
 
-func NewIndexedColumn[T ~string | *Expression](value T) *IndexedColumn {
-	if str, ok := any(value).(string); ok {
+// NewIndexedColumn creates an indexed column from a field name, a ColumnRef or an expression
+func NewIndexedColumn[T ~string | ColumnRef | *Expression](value T) *IndexedColumn {
+	switch v := any(value).(type) {
+	case *Expression:
 		return &IndexedColumn{
-			IndexedColumn: idxcol.NewIndexedColumnNames(str),
+			IndexedColumn: idxcol.NewIndexedColumnExpresions(v.Expression),
 		}
-	}
-	if expr, ok := any(value).(*Expression); ok {
+	case ColumnRef:
+		return &IndexedColumn{
+			IndexedColumn: idxcol.NewIndexedColumnNames(v.field),
+		}
+	default:
 		return &IndexedColumn{
-			IndexedColumn: idxcol.NewIndexedColumnExpresions(expr.Expression),
+			IndexedColumn: idxcol.NewIndexedColumnNames(reflect.ValueOf(value).String()),
 		}
 	}
-	return nil
 }
 
 type IndexedColumn struct {
@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	insertstmt "github.com/Nevoral/sqlofi/internal/sqlite/Insert"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	set "github.com/Nevoral/sqlofi/internal/sqlite/Set"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// INSERT_INTO creates an INSERT into the table of the model. The columns are the fields
+// of the model except generated and AUTOINCREMENT columns, SQLite computes those itself.
+// For a table given by its name or a *Table the columns are set with COLUMNS.
+func INSERT_INTO(model any) *Insert {
+	i := &Insert{
+		model: model,
+	}
+	if isModel(model) {
+		columns, err := column.FieldColumns(model)
+		if err != nil {
+			panic(err)
+		}
+		for _, col := range columns {
+			if col.Writable() {
+				i.fields = append(i.fields, col.Field)
+			}
+		}
+	}
+	i.Insert = insertstmt.NewInsert(reflectutil.GetTableName(model), i.fields)
+	return i
+}
+
+// INSERT_INTO creates an INSERT prefixed with the WITH clause
+func (w *With) INSERT_INTO(model any) *Insert {
+	i := INSERT_INTO(model)
+	i.Insert.With(w.With)
+	return i
+}
+
+// Insert is an INSERT statement, Build returns its SQL and the bound values
+type Insert struct {
+	*insertstmt.Insert
+	model  any
+	fields []string
+}
+
+// OR sets the conflict resolution, e.g. INSERT OR REPLACE or INSERT OR IGNORE
+func (i *Insert) OR(action ConflictClause) *Insert {
+	i.Insert.Or(string(action))
+	return i
+}
+
+// AS sets an alias for the table
+func (i *Insert) AS(alias string) *Insert {
+	i.Insert.Alias(alias)
+	return i
+}
+
+// COLUMNS replaces the inserted columns, given by their Go field names
+func (i *Insert) COLUMNS(fields ...string) *Insert {
+	if isModel(i.model) {
+		checkFields(i.model, fields)
+	}
+	i.fields = fields
+	i.Insert.Columns(fields...)
+	return i
+}
+
+// COLUMN_REFS replaces the inserted columns like COLUMNS, given by column references, e.g. UserCols.Email
+func (i *Insert) COLUMN_REFS(columns ...ColumnRef) *Insert {
+	return i.COLUMNS(columnFields(columns)...)
+}
+
+// VALUES appends a row of values, one for each column
+func (i *Insert) VALUES(values ...*Expression) *Insert {
+	row := make([]*expr.Expression, len(values))
+	for j, value := range values {
+		row[j] = value.Expression
+	}
+	i.Insert.Values(row...)
+	return i
+}
+
+// VALUES_FROM appends a row for each model, the values of its column fields are bound
+func (i *Insert) VALUES_FROM(models ...any) *Insert {
+	for _, model := range models {
+		value := modelValue(i.model, model, "VALUES_FROM")
+		row := make([]*expr.Expression, len(i.fields))
+		for j, field := range i.fields {
+			row[j] = bindField(value, field).Expression
+		}
+		i.Insert.Values(row...)
+	}
+	return i
+}
+
+// SELECT inserts the rows of the SELECT statement
+func (i *Insert) SELECT(selectStmt *Select) *Insert {
+	i.Insert.Select(selectStmt.Select)
+	return i
+}
+
+// DEFAULT_VALUES inserts one row of the column defaults
+func (i *Insert) DEFAULT_VALUES() *Insert {
+	i.Insert.DefaultValues()
+	return i
+}
+
+// ON_CONFLICT appends an upsert clause
+func (i *Insert) ON_CONFLICT(upsert *Upsert) *Insert {
+	i.Insert.OnConflict(upsert.Upsert)
+	return i
+}
+
+// RETURNING sets the columns returned for every inserted row
+func (i *Insert) RETURNING(columns ...*ResultColumn) *Insert {
+	i.Insert.Returning(resultColumns(columns)...)
+	return i
+}
+
+// isModel reports whether the table is given by a struct and not by its name or a *Table
+func isModel(model any) bool {
+	if _, ok := model.(*Table); ok {
+		return false
+	}
+	return reflect.Indirect(reflect.ValueOf(model)).Kind() == reflect.Struct
+}
+
+// checkFields panics when a field isn't present in the model
+func checkFields(model any, fields []string) {
+	names := reflectutil.GetStructFieldsNames(model)
+	for _, field := range fields {
+		if !slices.Contains(names, field) {
+			panic(fmt.Errorf("Error field %s isn't present in %s", field, reflectutil.GetStructName(model)))
+		}
+	}
+}
+
+// modelValue returns the struct value of v, it panics when it isn't of the type of the model
+func modelValue(model any, v any, clause string) reflect.Value {
+	if !isModel(model) {
+		panic(fmt.Errorf("Error %s for the table %v, it isn't a model", clause, model))
+	}
+	modelType := reflect.Indirect(reflect.ValueOf(model)).Type()
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Type() != modelType {
+		panic(fmt.Errorf("Error %s of %s for the model %s", clause, value.Type(), modelType))
+	}
+	return value
+}
+
+// bindField binds the value of the field of the struct
+func bindField(value reflect.Value, field string) *Expression {
+	return newExpression(&expr.Param{Param: "?", Value: value.FieldByName(field).Interface()})
+}
+
+func resultColumns(columns []*ResultColumn) []*selectstmt.ResultColumn {
+	covColumns := make([]*selectstmt.ResultColumn, len(columns))
+	for i, col := range columns {
+		covColumns[i] = col.ResultColumn
+	}
+	return covColumns
+}
+
+// UPSERT creates an ON CONFLICT clause for a conflict on the target columns, they have
+// to match a UNIQUE or PRIMARY KEY constraint. Without columns it handles any conflict
+// but it can only DO_NOTHING.
+func UPSERT[C ColumnName](target ...C) *Upsert {
+	return &Upsert{
+		Upsert: insertstmt.NewUpsert(columnNames(target)),
+	}
+}
+
+// Upsert is the ON CONFLICT clause of an INSERT
+type Upsert struct {
+	*insertstmt.Upsert
+}
+
+// TARGET_WHERE sets the condition of a partial unique index the target matches
+func (u *Upsert) TARGET_WHERE(condition *Expression) *Upsert {
+	u.Upsert.TargetWhere(condition.Expression)
+	return u
+}
+
+// DO_NOTHING skips the conflicting row
+func (u *Upsert) DO_NOTHING() *Upsert {
+	u.Upsert.DoNothing()
+	return u
+}
+
+// DO_UPDATE updates the existing row instead, EXCLUDED refers to the inserted values
+func (u *Upsert) DO_UPDATE(assignments ...*Assignment) *Upsert {
+	u.Upsert.DoUpdate(setAssignments(assignments)...)
+	return u
+}
+
+// WHERE limits DO_UPDATE to the existing rows matching the condition
+func (u *Upsert) WHERE(condition *Expression) *Upsert {
+	u.Upsert.Where(condition.Expression)
+	return u
+}
+
+// SET assigns the value to the column
+func SET[C ColumnName](column C, value *Expression) *Assignment {
+	return &Assignment{
+		Assignment: set.NewAssignment([]string{columnName(column)}, value.Expression),
+	}
+}
+
+// SET_ROW assigns a row value to the columns, e.g. SET_ROW(columns, Expressions(a, b))
+func SET_ROW[C ColumnName](columns []C, value *Expression) *Assignment {
+	return &Assignment{
+		Assignment: set.NewAssignment(columnNames(columns), value.Expression),
+	}
+}
+
+// Assignment is a column = expression of the SET clause
+type Assignment struct {
+	*set.Assignment
+}
+
+func setAssignments(assignments []*Assignment) []*set.Assignment {
+	covAssignments := make([]*set.Assignment, len(assignments))
+	for i, assignment := range assignments {
+		covAssignments[i] = assignment.Assignment
+	}
+	return covAssignments
+}
+
+// EXCLUDED refers to the value of the column the conflicting INSERT tried to insert
+func EXCLUDED[C ColumnName](column C) *Expression {
+	return newExpression(&expr.Column{Table: "excluded", Name: columnName(column)})
+}
+
+// columnName returns the unqualified SQL name of the column
+func columnName[C ColumnName](column C) string {
+	return utils.ToSnakeCase(columnField(column))
+}
+
+func columnNames[C ColumnName](columns []C) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = columnName(column)
+	}
+	return names
+}
@@ -0,0 +1,27 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+type insertUser struct {
+	Id    int64  `sqlofi:"PRIMARY KEY AUTOINCREMENT"`
+	Email string `sqlofi:"NOT NULL"`
+	Name  string `sqlofi:"NOT NULL"`
+}
+
+func TestInsertColumnRefs(t *testing.T) {
+	email := ColOf[insertUser]("Email")
+	query, args := INSERT_INTO(insertUser{}).
+		COLUMN_REFS(email).
+		VALUES_FROM(insertUser{Email: "ada@example.com", Name: "Ada"}).
+		Build()
+
+	if want := "INSERT INTO insert_user (email) VALUES (?)"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if want := []any{"ada@example.com"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %#v, want %#v", args, want)
+	}
+}
@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"iter"
+)
+
+// Iter runs the statement and yields its rows one by one, scanned into a T like Query.
+// The rows are closed when the loop ends, also when it breaks early. An error ends the
+// sequence, it's yielded with the zero T.
+func Iter[T any](ctx context.Context, db Querier, stmt Statement) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		query, args := stmt.Build()
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		scan, err := newRowScanner[T](rows)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			var row T
+			if err := scan(&row); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(row, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// IterChunks yields the rows of the statement like Iter in chunks of up to size rows,
+// the last chunk can be shorter. Every chunk is a new slice the loop can keep.
+func IterChunks[T any](ctx context.Context, db Querier, stmt Statement, size int) iter.Seq2[[]T, error] {
+	if size <= 0 {
+		size = 1
+	}
+	return func(yield func([]T, error) bool) {
+		chunk := make([]T, 0, size)
+		for row, err := range Iter[T](ctx, db, stmt) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			chunk = append(chunk, row)
+			if len(chunk) == size {
+				if !yield(chunk, nil) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk, nil)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// numbers selects the integers 1 to 10
+const numbers rawQuery = "WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n WHERE x < 10) SELECT x FROM n"
+
+func TestIterBreakClosesRows(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	var got []int64
+	for x, err := range Iter[int64](ctx, db, numbers) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, x)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if inUse := db.Stats().InUse; inUse != 0 {
+		t.Errorf("%d connections in use after break, want 0", inUse)
+	}
+}
+
+func TestIterChunksShortLastChunk(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	var sizes []int
+	for chunk, err := range IterChunks[int64](ctx, db, numbers, 4) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(chunk))
+	}
+	if want := []int{4, 4, 2}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("chunk sizes = %v, want %v", sizes, want)
+	}
+	if inUse := db.Stats().InUse; inUse != 0 {
+		t.Errorf("%d connections in use after the loop, want 0", inUse)
+	}
+}
@@ -0,0 +1,222 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// Querier runs queries, it's implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Statement is a statement returning its SQL and bound values, e.g. *Select or
+// an *Insert, *Update or *Delete with RETURNING
+type Statement interface {
+	Build() (string, []any)
+}
+
+// Query runs the statement and scans every row into a T. Result columns are matched to the
+// fields by the column naming of CREATE_TABLE, e.g. Email and email or CategoryId and category_id.
+// Fields of embedded structs are matched like fields of T, fields tagged sqlofi:"-" are skipped.
+// A result column without a field is an error. When T isn't a struct the result has to
+// have a single column, e.g. Query[int64] for SELECT count(*).
+func Query[T any](ctx context.Context, db Querier, stmt Statement) ([]T, error) {
+	query, args := stmt.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []T
+	for rows.Next() {
+		var row T
+		if err := scan(&row); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryOne runs the statement and scans its first row into a T like Query,
+// it returns sql.ErrNoRows when there is no row
+func QueryOne[T any](ctx context.Context, db Querier, stmt Statement) (T, error) {
+	var row T
+
+	query, args := stmt.Build()
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return row, err
+	}
+	defer rows.Close()
+
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return row, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return row, err
+		}
+		return row, sql.ErrNoRows
+	}
+	if err := scan(&row); err != nil {
+		return row, err
+	}
+	return row, rows.Close()
+}
+
+// newRowScanner returns a function scanning the current row of the rows into a T
+func newRowScanner[T any](rows *sql.Rows) (func(*T) error, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	rowType := reflect.TypeFor[T]()
+	if !isStructRow(rowType) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("result with %d columns can't be scanned into %s, it isn't a struct", len(columns), rowType)
+		}
+		return func(row *T) error {
+			return rows.Scan(row)
+		}, nil
+	}
+
+	plan := scanPlanOf(indirectType(rowType))
+	indexes := make([][]int, len(columns))
+	mapped := make(map[string]string, len(columns))
+	for i, column := range columns {
+		name := utils.ToSnakeCase(column)
+		index, ok := plan[name]
+		if !ok {
+			return nil, fmt.Errorf("result column '%s' has no field in %s", column, rowType)
+		}
+		if previous, ok := mapped[name]; ok {
+			return nil, fmt.Errorf("result columns '%s' and '%s' map to the same field of %s", previous, column, rowType)
+		}
+		mapped[name] = column
+		indexes[i] = index
+	}
+
+	targets := make([]any, len(columns))
+	return func(row *T) error {
+		value := reflect.ValueOf(row).Elem()
+		if value.Kind() == reflect.Ptr {
+			value.Set(reflect.New(value.Type().Elem()))
+			value = value.Elem()
+		}
+		for i, index := range indexes {
+			targets[i] = fieldByIndex(value, index).Addr().Interface()
+		}
+		return rows.Scan(targets...)
+	}, nil
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	scanPlans   sync.Map // reflect.Type -> map[string][]int
+)
+
+// scanPlanOf returns the field index of every column name of the struct type, it's cached per type
+func scanPlanOf(structType reflect.Type) map[string][]int {
+	if plan, ok := scanPlans.Load(structType); ok {
+		return plan.(map[string][]int)
+	}
+	plan := make(map[string][]int)
+	collectFields(structType, plan)
+	actual, _ := scanPlans.LoadOrStore(structType, plan)
+	return actual.(map[string][]int)
+}
+
+// collectFields adds the fields of the struct type level by level, so a field of an
+// outer struct takes precedence over a promoted field of an embedded one like in Go
+func collectFields(structType reflect.Type, plan map[string][]int) {
+	type level struct {
+		structType reflect.Type
+		index      []int
+	}
+	queue := []level{{structType: structType}}
+
+	for len(queue) > 0 {
+		var next []level
+		found := make(map[string][]int)
+		for _, current := range queue {
+			for i := range current.structType.NumField() {
+				field := current.structType.Field(i)
+				if tag, ok := field.Tag.Lookup("sqlofi"); ok && tag == "-" {
+					continue
+				}
+				index := append(append([]int{}, current.index...), i)
+				if field.Anonymous && isStructRow(field.Type) {
+					// A nil pointer to an unexported struct can't be allocated while scanning
+					if field.IsExported() || field.Type.Kind() != reflect.Ptr {
+						next = append(next, level{structType: indirectType(field.Type), index: index})
+					}
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+				name := utils.ToSnakeCase(field.Name)
+				if _, ok := found[name]; !ok {
+					found[name] = index
+				}
+			}
+		}
+		for name, index := range found {
+			if _, ok := plan[name]; !ok {
+				plan[name] = index
+			}
+		}
+		queue = next
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// isStructRow reports whether the type is scanned field by field, structs implementing
+// sql.Scanner like sql.NullString and time.Time are scanned as a single value
+func isStructRow(t reflect.Type) bool {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct || reflect.PointerTo(t).Implements(scannerType) {
+		return false
+	}
+	return t != reflect.TypeFor[time.Time]()
+}
+
+// fieldByIndex returns the field of the index path, it allocates nil embedded pointers
+func fieldByIndex(value reflect.Value, index []int) reflect.Value {
+	for _, x := range index {
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value
+}
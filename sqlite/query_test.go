@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rawQuery is a Statement of hand written SQL
+type rawQuery string
+
+func (q rawQuery) Build() (string, []any) {
+	return string(q), nil
+}
+
+type queryBase struct {
+	Id   int64
+	Name string
+}
+
+type queryRow struct {
+	queryBase
+	Name     string // shadows queryBase.Name
+	Nickname sql.NullString
+}
+
+func TestQueryEmbeddedStruct(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	rows, err := Query[queryRow](ctx, db, rawQuery("SELECT 1 AS id, 'outer' AS name, 'ada' AS nickname UNION ALL SELECT 2, 'other', NULL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Id != 1 || rows[0].Name != "outer" || rows[0].queryBase.Name != "" {
+		t.Errorf("row = %+v, want the name in the outer field", rows[0])
+	}
+	if rows[0].Nickname != (sql.NullString{String: "ada", Valid: true}) || rows[1].Nickname.Valid {
+		t.Errorf("nicknames = %+v and %+v, want ada and NULL", rows[0].Nickname, rows[1].Nickname)
+	}
+	if _, ok := scanPlans.Load(reflect.TypeFor[queryRow]()); !ok {
+		t.Error("the scan plan of queryRow isn't cached")
+	}
+}
+
+func TestQueryTime(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	at := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+	if _, err := db.ExecContext(ctx, "CREATE TABLE event (id INTEGER, at TIMESTAMP)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "INSERT INTO event (id, at) VALUES (1, ?)", at); err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct {
+		Id int64
+		At time.Time
+	}
+	got, err := QueryOne[event](ctx, db, rawQuery("SELECT id, at FROM event"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.At.Equal(at) {
+		t.Errorf("At = %v, want %v", got.At, at)
+	}
+}
+
+func TestQueryColumnErrors(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	tests := []struct {
+		name  string
+		query rawQuery
+		want  string
+	}{
+		{"unmapped column", "SELECT 1 AS id, 2 AS missing", "result column 'missing' has no field"},
+		{"two columns for one field", "SELECT 1 AS id, 'a' AS nick_name, 'b' AS NickName", "map to the same field"},
+	}
+	type row struct {
+		Id       int64
+		NickName string
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Query[row](ctx, db, tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryOneNoRows(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	_, err := QueryOne[int64](ctx, db, rawQuery("SELECT 1 WHERE false"))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("error = %v, want sql.ErrNoRows", err)
+	}
+}
@@ -0,0 +1,269 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+)
+
+// Executor runs statements, it's implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Executor interface {
+	Querier
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// NewRepo creates a repository of the model T on the database or transaction. The primary key
+// is taken from the PRIMARY KEY column tags or the PRIMARY KEY constraint of TableOptions,
+// it panics when T isn't a model with a primary key.
+func NewRepo[T any](db Executor) *Repo[T] {
+	var model T
+	if !isModel(model) {
+		panic(fmt.Errorf("Error repository of %s, it isn't a struct", reflect.TypeFor[T]()))
+	}
+
+	columns, err := column.FieldColumns(model)
+	if err != nil {
+		panic(err)
+	}
+	r := &Repo[T]{
+		db:      db,
+		model:   model,
+		table:   reflectutil.GetTableName(model),
+		columns: columns,
+	}
+
+	for _, col := range columns {
+		if col.PrimaryKey {
+			r.key = append(r.key, col)
+		}
+	}
+	for _, constraint := range CREATE_TABLE(model).Constraints() {
+		if constraint.PrimaryKey == nil || len(r.key) > 0 {
+			continue
+		}
+		for _, idxColumn := range constraint.PrimaryKey.Columns() {
+			for _, col := range columns {
+				if col.Name == idxColumn.ColumnName() {
+					r.key = append(r.key, col)
+				}
+			}
+		}
+	}
+	if len(r.key) == 0 {
+		panic(fmt.Errorf("Error repository of %s, the table %s has no PRIMARY KEY", reflectutil.GetStructName(model), r.table))
+	}
+	return r
+}
+
+// Repo reads and writes the rows of the table of the model T
+type Repo[T any] struct {
+	db      Executor
+	model   T
+	table   string
+	columns []column.FieldColumn
+	key     []column.FieldColumn
+}
+
+// Insert inserts the row, the value SQLite assigned to the AUTOINCREMENT key is set back to v
+func (r *Repo[T]) Insert(ctx context.Context, v *T) error {
+	query, args := INSERT_INTO(r.model).VALUES_FROM(v).Build()
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return r.setAutoincrement(v, result)
+}
+
+// InsertMany inserts the rows one by one like Insert, a Repo on a *sql.Tx makes it atomic
+func (r *Repo[T]) InsertMany(ctx context.Context, vs []*T) error {
+	for _, v := range vs {
+		if err := r.Insert(ctx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the row with the primary key, the values are in the order of the key columns.
+// It returns sql.ErrNoRows when there is no such row.
+func (r *Repo[T]) Get(ctx context.Context, key ...any) (T, error) {
+	where, err := r.keyCondition(key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return QueryOne[T](ctx, r.db, r.selectRows(where))
+}
+
+// Update writes every column of v except the primary key, generated and AUTOINCREMENT
+// columns to the row with the primary key of v. It returns sql.ErrNoRows when there is no such row.
+func (r *Repo[T]) Update(ctx context.Context, v *T) error {
+	where, err := r.keyCondition(r.keyValues(v))
+	if err != nil {
+		return err
+	}
+	query, args := UPDATE(r.model).SetFromStruct(v).WHERE(where).Build()
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Upsert inserts the row or updates the row with its primary key. An AUTOINCREMENT key
+// with the zero value is always inserted and its new value set back to v.
+func (r *Repo[T]) Upsert(ctx context.Context, v *T) error {
+	value := reflect.ValueOf(v).Elem()
+
+	var fields, keys, updated []string
+	newRow := false
+	for _, col := range r.columns {
+		switch {
+		case col.Autoincrement && col.PrimaryKey:
+			if value.FieldByName(col.Field).IsZero() {
+				newRow = true
+				continue
+			}
+			fields = append(fields, col.Field)
+		case col.Writable():
+			fields = append(fields, col.Field)
+			if !col.PrimaryKey {
+				updated = append(updated, col.Field)
+			}
+		}
+	}
+	if newRow {
+		return r.Insert(ctx, v)
+	}
+
+	for _, col := range r.key {
+		keys = append(keys, col.Field)
+	}
+	upsert := UPSERT(keys...)
+	if len(updated) == 0 {
+		upsert.DO_NOTHING()
+	} else {
+		assignments := make([]*Assignment, len(updated))
+		for i, field := range updated {
+			assignments[i] = SET(field, EXCLUDED(field))
+		}
+		upsert.DO_UPDATE(assignments...)
+	}
+
+	query, args := INSERT_INTO(r.model).COLUMNS(fields...).VALUES_FROM(v).ON_CONFLICT(upsert).Build()
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Delete deletes the row with the primary key, it returns sql.ErrNoRows when there is no such row
+func (r *Repo[T]) Delete(ctx context.Context, key ...any) error {
+	where, err := r.keyCondition(key)
+	if err != nil {
+		return err
+	}
+	query, args := DELETE_FROM(r.model).WHERE(where).Build()
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Find returns the rows matching the condition, all rows when it's nil
+func (r *Repo[T]) Find(ctx context.Context, where *Expression) ([]T, error) {
+	return Query[T](ctx, r.db, r.selectRows(where))
+}
+
+// Count returns the number of rows matching the condition, of all rows when it's nil
+func (r *Repo[T]) Count(ctx context.Context, where *Expression) (int64, error) {
+	stmt := SELECT(NOTHING, NewExpressionColumn(COUNT_ALL())).FROM(NewTableFrom(r.table))
+	if where != nil {
+		stmt.WHERE(where)
+	}
+	return QueryOne[int64](ctx, r.db, stmt)
+}
+
+// Exists reports whether a row matches the condition
+func (r *Repo[T]) Exists(ctx context.Context, where *Expression) (bool, error) {
+	rows := SELECT(NOTHING, NewExpressionColumn(Expr(1))).FROM(NewTableFrom(r.table))
+	if where != nil {
+		rows.WHERE(where)
+	}
+	return QueryOne[bool](ctx, r.db, SELECT(NOTHING, NewExpressionColumn(EXISTS(rows))))
+}
+
+// selectRows selects the model columns of the rows matching the condition
+func (r *Repo[T]) selectRows(where *Expression) *Select {
+	columns := make([]*ResultColumn, len(r.columns))
+	for i, col := range r.columns {
+		columns[i] = NewExpressionColumn(Expr(NewColumnRef(r.table, col.Field)))
+	}
+	stmt := SELECT(NOTHING, columns...).FROM(NewTableFrom(r.table))
+	if where != nil {
+		stmt.WHERE(where)
+	}
+	return stmt
+}
+
+// keyCondition returns key column = value for every column of the primary key
+func (r *Repo[T]) keyCondition(key []any) (*Expression, error) {
+	if len(key) != len(r.key) {
+		return nil, fmt.Errorf("primary key of %s has %d columns, got %d values", r.table, len(r.key), len(key))
+	}
+	conditions := make([]*Expression, len(key))
+	for i, col := range r.key {
+		conditions[i] = EQ(Expr(NewColumnRef(r.table, col.Field)), newExpression(&expr.Param{Param: "?", Value: key[i]}))
+	}
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+	return AND(conditions...), nil
+}
+
+// keyValues returns the primary key values of v
+func (r *Repo[T]) keyValues(v *T) []any {
+	value := reflect.ValueOf(v).Elem()
+	key := make([]any, len(r.key))
+	for i, col := range r.key {
+		key[i] = value.FieldByName(col.Field).Interface()
+	}
+	return key
+}
+
+// setAutoincrement sets the AUTOINCREMENT key of v to the id of the inserted row
+func (r *Repo[T]) setAutoincrement(v *T, result sql.Result) error {
+	for _, col := range r.key {
+		if !col.Autoincrement {
+			continue
+		}
+		field := reflect.ValueOf(v).Elem().FieldByName(col.Field)
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		switch field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(id)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(id))
+		default:
+			return fmt.Errorf("AUTOINCREMENT column %s of %s can't hold the id %d", col.Name, r.table, id)
+		}
+	}
+	return nil
+}
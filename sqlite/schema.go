@@ -7,24 +7,32 @@ import (
 	"os"
 	"time"
 
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
 	index "github.com/Nevoral/sqlofi/internal/sqlite/Index"
 	pragmas "github.com/Nevoral/sqlofi/internal/sqlite/Pragmas"
 	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
+	trigger "github.com/Nevoral/sqlofi/internal/sqlite/Trigger"
+	view "github.com/Nevoral/sqlofi/internal/sqlite/View"
 )
 
 func NewSchema(name string) *Schema {
 	return &Schema{
-		name: name,
+		name:  name,
+		enums: enum.Registry{},
 	}
 }
 
 type Schema struct {
-	name    string
-	db      *sql.DB
-	ctx     context.Context
-	pragmas []*pragmas.Pragma
-	tables  []*table.Table
-	indexes []*index.Index
+	name     string
+	db       *sql.DB
+	ctx      context.Context
+	pragmas  []*pragmas.Pragma
+	tables   []*table.Table
+	indexes  []*index.Index
+	views    []*view.View
+	triggers []*trigger.Trigger
+	enums    enum.Registry
 }
 
 func (s *Schema) Pragma(pragmas ...*Pragma) *Schema {
@@ -37,6 +45,18 @@ func (s *Schema) Pragma(pragmas ...*Pragma) *Schema {
 func (s *Schema) Table(tables ...*Table) *Schema {
 	for _, tab := range tables {
 		s.tables = append(s.tables, tab.Table)
+		if indexer, ok := reflectutil.Implements[TableIndexer](tab.Model()); ok {
+			s.Index(indexer.Indexes()...)
+		}
+	}
+	return s
+}
+
+// Model creates the tables of the models, the models can reference each other
+// as foreign tables and configure themselves through TableOptioner and TableIndexer.
+func (s *Schema) Model(models ...any) *Schema {
+	for _, model := range models {
+		s.Table(CREATE_TABLE(model, models...))
 	}
 	return s
 }
@@ -48,6 +68,31 @@ func (s *Schema) Index(indexes ...*Index) *Schema {
 	return s
 }
 
+// View adds views, they are created after the tables and indexes
+func (s *Schema) View(views ...*View) *Schema {
+	for _, v := range views {
+		s.views = append(s.views, v.View)
+	}
+	return s
+}
+
+// Trigger adds triggers, they are created last
+func (s *Schema) Trigger(triggers ...*Trigger) *Schema {
+	for _, t := range triggers {
+		s.triggers = append(s.triggers, t.Trigger)
+	}
+	return s
+}
+
+// Enum registers the allowed values of enum types, every table column
+// of a registered type gets a CHECK constraint listing those values.
+func (s *Schema) Enum(enums ...*Enum) *Schema {
+	for _, e := range enums {
+		s.enums.Register(e.typ, e.values)
+	}
+	return s
+}
+
 func (s *Schema) OpenDBConnection(driverName, dataSourceName string) (err error) {
 	s.db, err = sql.Open(driverName, dataSourceName)
 	return err
@@ -81,11 +126,22 @@ func (s *Schema) SetUpDatabase() *Schema {
 		s.db.Exec(pragma.Build())
 	}
 	for _, table := range s.tables {
-		s.db.Exec(table.Build())
+		s.db.Exec(table.BuildEnums(s.enums))
 	}
 	for _, index := range s.indexes {
 		s.db.Exec(index.Build())
 	}
+	for _, table := range s.tables {
+		for _, index := range table.Indexes() {
+			s.db.Exec(index.Build())
+		}
+	}
+	for _, view := range s.views {
+		s.db.Exec(view.Build())
+	}
+	for _, trigger := range s.triggers {
+		s.db.Exec(trigger.Build())
+	}
 	return s
 }
 
@@ -96,10 +152,21 @@ func (s *Schema) Build() string {
 	}
 	schema += "\n"
 	for _, table := range s.tables {
-		schema += fmt.Sprintf("%s;\n\n", table.Build())
+		schema += fmt.Sprintf("%s;\n\n", table.BuildEnums(s.enums))
 	}
 	for _, index := range s.indexes {
 		schema += fmt.Sprintf("%s;\n", index.Build())
 	}
+	for _, table := range s.tables {
+		for _, index := range table.Indexes() {
+			schema += fmt.Sprintf("%s;\n", index.Build())
+		}
+	}
+	for _, view := range s.views {
+		schema += fmt.Sprintf("\n%s;\n", view.Build())
+	}
+	for _, trigger := range s.triggers {
+		schema += fmt.Sprintf("\n%s;\n", trigger.Build())
+	}
 	return schema
 }
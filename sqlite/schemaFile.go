@@ -0,0 +1,818 @@
+package sqlite
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	enum "github.com/Nevoral/sqlofi/internal/sqlite/Enum"
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+	foreignkey "github.com/Nevoral/sqlofi/internal/sqlite/ForeignKey"
+	generated "github.com/Nevoral/sqlofi/internal/sqlite/Generated"
+	index "github.com/Nevoral/sqlofi/internal/sqlite/Index"
+	idxcol "github.com/Nevoral/sqlofi/internal/sqlite/IndexedColumn"
+	pragmas "github.com/Nevoral/sqlofi/internal/sqlite/Pragmas"
+	primarykey "github.com/Nevoral/sqlofi/internal/sqlite/PrimaryKey"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	sortorder "github.com/Nevoral/sqlofi/internal/sqlite/SortOrder"
+	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
+	trigger "github.com/Nevoral/sqlofi/internal/sqlite/Trigger"
+	types "github.com/Nevoral/sqlofi/internal/sqlite/Types"
+	unique "github.com/Nevoral/sqlofi/internal/sqlite/Unique"
+	view "github.com/Nevoral/sqlofi/internal/sqlite/View"
+	"github.com/Nevoral/sqlofi/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed sqlofi.schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON Schema describing the schema files,
+// editors use it to validate and complete the files.
+func JSONSchema() []byte {
+	return bytes.Clone(jsonSchema)
+}
+
+// SchemaFormat is the encoding of a schema file
+type SchemaFormat string
+
+const (
+	JSON_FORMAT SchemaFormat = "json"
+	YAML_FORMAT SchemaFormat = "yaml"
+)
+
+// SchemaFile is the schema in the form of a JSON or YAML file.
+// Tables loaded from a file are dynamic tables, the statements of views,
+// triggers and CREATE TABLE ... AS tables are kept as raw SQL.
+type SchemaFile struct {
+	JSONSchema string       `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Name       string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Pragmas    []PragmaDef  `json:"pragmas,omitempty" yaml:"pragmas,omitempty"`
+	Tables     []TableDef   `json:"tables,omitempty" yaml:"tables,omitempty"`
+	Indexes    []IndexDef   `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+	Views      []ViewDef    `json:"views,omitempty" yaml:"views,omitempty"`
+	Triggers   []TriggerDef `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+}
+
+// PragmaDef is a PRAGMA statement, at most one of Value and Argument is set
+type PragmaDef struct {
+	Schema   string `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Name     string `json:"name" yaml:"name"`
+	Value    string `json:"value,omitempty" yaml:"value,omitempty"`
+	Argument string `json:"argument,omitempty" yaml:"argument,omitempty"`
+}
+
+// TableDef is a CREATE TABLE statement, either with columns or with As
+type TableDef struct {
+	Name         string               `json:"name" yaml:"name"`
+	Schema       string               `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Temporary    bool                 `json:"temporary,omitempty" yaml:"temporary,omitempty"`
+	IfNotExists  bool                 `json:"if_not_exists,omitempty" yaml:"if_not_exists,omitempty"`
+	WithoutRowID bool                 `json:"without_rowid,omitempty" yaml:"without_rowid,omitempty"`
+	Strict       bool                 `json:"strict,omitempty" yaml:"strict,omitempty"`
+	As           string               `json:"as,omitempty" yaml:"as,omitempty"`
+	Columns      []ColumnDef          `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Constraints  []TableConstraintDef `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+// ColumnDef is a column with its constraints in declaration order
+type ColumnDef struct {
+	Name        string                `json:"name" yaml:"name"`
+	Type        string                `json:"type" yaml:"type"`
+	Constraints []ColumnConstraintDef `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+// ColumnConstraintDef is a single column constraint, exactly one of its kinds is set
+type ColumnConstraintDef struct {
+	Name       string         `json:"name,omitempty" yaml:"name,omitempty"`
+	PrimaryKey *PrimaryKeyDef `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	NotNull    *ConflictDef   `json:"not_null,omitempty" yaml:"not_null,omitempty"`
+	Unique     *ConflictDef   `json:"unique,omitempty" yaml:"unique,omitempty"`
+	Check      string         `json:"check,omitempty" yaml:"check,omitempty"`
+	Default    string         `json:"default,omitempty" yaml:"default,omitempty"`
+	Collate    string         `json:"collate,omitempty" yaml:"collate,omitempty"`
+	References *ReferencesDef `json:"references,omitempty" yaml:"references,omitempty"`
+	Generated  *GeneratedDef  `json:"generated,omitempty" yaml:"generated,omitempty"`
+}
+
+// PrimaryKeyDef is the PRIMARY KEY column constraint
+type PrimaryKeyDef struct {
+	Order         string `json:"order,omitempty" yaml:"order,omitempty"`
+	OnConflict    string `json:"on_conflict,omitempty" yaml:"on_conflict,omitempty"`
+	Autoincrement bool   `json:"autoincrement,omitempty" yaml:"autoincrement,omitempty"`
+}
+
+// ConflictDef is a constraint whose only option is its conflict clause
+type ConflictDef struct {
+	OnConflict string `json:"on_conflict,omitempty" yaml:"on_conflict,omitempty"`
+}
+
+// ReferencesDef is the REFERENCES clause of a foreign key
+type ReferencesDef struct {
+	Table         string   `json:"table" yaml:"table"`
+	Columns       []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	OnDelete      string   `json:"on_delete,omitempty" yaml:"on_delete,omitempty"`
+	OnUpdate      string   `json:"on_update,omitempty" yaml:"on_update,omitempty"`
+	Match         string   `json:"match,omitempty" yaml:"match,omitempty"`
+	Deferrable    *string  `json:"deferrable,omitempty" yaml:"deferrable,omitempty"`
+	NotDeferrable *string  `json:"not_deferrable,omitempty" yaml:"not_deferrable,omitempty"`
+}
+
+// GeneratedDef is a generated column
+type GeneratedDef struct {
+	Expression string `json:"expression" yaml:"expression"`
+	Always     bool   `json:"always,omitempty" yaml:"always,omitempty"`
+	Storage    string `json:"storage,omitempty" yaml:"storage,omitempty"`
+}
+
+// TableConstraintDef is a single table constraint, exactly one of its kinds is set
+type TableConstraintDef struct {
+	Name       string              `json:"name,omitempty" yaml:"name,omitempty"`
+	PrimaryKey *KeyDef             `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	Unique     *KeyDef             `json:"unique,omitempty" yaml:"unique,omitempty"`
+	Check      string              `json:"check,omitempty" yaml:"check,omitempty"`
+	ForeignKey *TableForeignKeyDef `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+}
+
+// KeyDef is a PRIMARY KEY or UNIQUE table constraint
+type KeyDef struct {
+	Columns    []IndexedColumnDef `json:"columns" yaml:"columns"`
+	OnConflict string             `json:"on_conflict,omitempty" yaml:"on_conflict,omitempty"`
+}
+
+// TableForeignKeyDef is a FOREIGN KEY table constraint
+type TableForeignKeyDef struct {
+	Columns    []string      `json:"columns" yaml:"columns"`
+	References ReferencesDef `json:"references" yaml:"references"`
+}
+
+// IndexedColumnDef is a column or an expression of an index or key, exactly one of them is set
+type IndexedColumnDef struct {
+	Column     string `json:"column,omitempty" yaml:"column,omitempty"`
+	Expression string `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Collate    string `json:"collate,omitempty" yaml:"collate,omitempty"`
+	Order      string `json:"order,omitempty" yaml:"order,omitempty"`
+}
+
+// IndexDef is a CREATE INDEX statement
+type IndexDef struct {
+	Name        string             `json:"name" yaml:"name"`
+	Table       string             `json:"table" yaml:"table"`
+	Schema      string             `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Unique      bool               `json:"unique,omitempty" yaml:"unique,omitempty"`
+	IfNotExists bool               `json:"if_not_exists,omitempty" yaml:"if_not_exists,omitempty"`
+	Columns     []IndexedColumnDef `json:"columns" yaml:"columns"`
+	Where       string             `json:"where,omitempty" yaml:"where,omitempty"`
+}
+
+// ViewDef is a CREATE VIEW statement
+type ViewDef struct {
+	Name        string   `json:"name" yaml:"name"`
+	Schema      string   `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Temporary   bool     `json:"temporary,omitempty" yaml:"temporary,omitempty"`
+	IfNotExists bool     `json:"if_not_exists,omitempty" yaml:"if_not_exists,omitempty"`
+	Columns     []string `json:"columns,omitempty" yaml:"columns,omitempty"`
+	Select      string   `json:"select" yaml:"select"`
+}
+
+// TriggerDef is a CREATE TRIGGER statement
+type TriggerDef struct {
+	Name        string   `json:"name" yaml:"name"`
+	Schema      string   `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Temporary   bool     `json:"temporary,omitempty" yaml:"temporary,omitempty"`
+	IfNotExists bool     `json:"if_not_exists,omitempty" yaml:"if_not_exists,omitempty"`
+	Timing      string   `json:"timing,omitempty" yaml:"timing,omitempty"`
+	Event       string   `json:"event" yaml:"event"`
+	Of          []string `json:"of,omitempty" yaml:"of,omitempty"`
+	Table       string   `json:"table" yaml:"table"`
+	ForEachRow  bool     `json:"for_each_row,omitempty" yaml:"for_each_row,omitempty"`
+	When        string   `json:"when,omitempty" yaml:"when,omitempty"`
+	Statements  []string `json:"statements" yaml:"statements"`
+}
+
+// LoadSchemaFile reads a schema file, the format is picked by the
+// extension: .json, .yaml or .yml
+func LoadSchemaFile(path string) (*Schema, error) {
+	format, err := formatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadSchema(data, format)
+}
+
+// LoadSchema decodes a schema file in the given format
+func LoadSchema(data []byte, format SchemaFormat) (*Schema, error) {
+	var def SchemaFile
+	switch format {
+	case JSON_FORMAT:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&def); err != nil {
+			return nil, fmt.Errorf("schema file: %w", err)
+		}
+	case YAML_FORMAT:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&def); err != nil {
+			return nil, fmt.Errorf("schema file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown schema format '%s'", format)
+	}
+	return NewSchemaFromFile(&def)
+}
+
+// ExportFile writes the schema to a file, the format is picked by the
+// extension: .json, .yaml or .yml
+func (s *Schema) ExportFile(path string) error {
+	format, err := formatOf(path)
+	if err != nil {
+		return err
+	}
+	data, err := s.Export(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Export encodes the schema as a schema file in the given format,
+// loading it back gives a Schema with the same Build output.
+func (s *Schema) Export(format SchemaFormat) ([]byte, error) {
+	def := s.File()
+	switch format {
+	case JSON_FORMAT:
+		data, err := json.MarshalIndent(def, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case YAML_FORMAT:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(def); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown schema format '%s'", format)
+	}
+}
+
+func formatOf(path string) (SchemaFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return JSON_FORMAT, nil
+	case ".yaml", ".yml":
+		return YAML_FORMAT, nil
+	default:
+		return "", fmt.Errorf("schema file '%s' has to end with .json, .yaml or .yml", path)
+	}
+}
+
+// File describes the schema as a SchemaFile. Enum checks and the indexes
+// declared in tags become ordinary CHECK constraints and indexes.
+func (s *Schema) File() *SchemaFile {
+	def := &SchemaFile{
+		Name: s.name,
+	}
+
+	for _, p := range s.pragmas {
+		def.Pragmas = append(def.Pragmas, PragmaDef{
+			Schema:   p.SchemaName(),
+			Name:     p.Name(),
+			Value:    p.Value(),
+			Argument: p.Argument(),
+		})
+	}
+
+	for _, t := range s.tables {
+		def.Tables = append(def.Tables, tableDef(t, s.enums))
+	}
+
+	for _, idx := range s.indexes {
+		def.Indexes = append(def.Indexes, indexDef(idx))
+	}
+	for _, t := range s.tables {
+		for _, idx := range t.Indexes() {
+			def.Indexes = append(def.Indexes, indexDef(idx))
+		}
+	}
+
+	for _, v := range s.views {
+		def.Views = append(def.Views, ViewDef{
+			Name:        v.Name(),
+			Schema:      v.SchemaName(),
+			Temporary:   v.IsTemporary(),
+			IfNotExists: v.IsIfNotExists(),
+			Columns:     v.ColumnNames(),
+			Select:      v.SelectStatement().Inline(),
+		})
+	}
+
+	for _, t := range s.triggers {
+		trig := TriggerDef{
+			Name:        t.Name(),
+			Schema:      t.SchemaName(),
+			Temporary:   t.IsTemporary(),
+			IfNotExists: t.IsIfNotExists(),
+			Timing:      string(t.Timing()),
+			Event:       string(t.Event()),
+			Of:          t.ColumnNames(),
+			Table:       tableNameOf(t.Table()),
+			ForEachRow:  t.IsForEachRow(),
+			Statements:  t.Statements(),
+		}
+		if when := t.WhenExpression(); when != nil {
+			trig.When = when.Inline()
+		}
+		def.Triggers = append(def.Triggers, trig)
+	}
+	return def
+}
+
+func tableDef(t *table.Table, enums enum.Registry) TableDef {
+	def := TableDef{
+		Name:         t.TableName(),
+		Schema:       t.SchemaName(),
+		Temporary:    t.IsTemporary(),
+		IfNotExists:  t.IsIfNotExists(),
+		WithoutRowID: t.IsWithoutRowID(),
+		Strict:       t.IsStrict(),
+	}
+	if statement := t.SelectStatement(); statement != nil {
+		def.As = statement.Inline()
+		return def
+	}
+
+	for _, col := range t.EnumColumns(enums) {
+		colDef := ColumnDef{
+			Name: col.Name(),
+			Type: col.Type().String(),
+		}
+		for _, constraint := range col.Constraints() {
+			colDef.Constraints = append(colDef.Constraints, columnConstraintDef(constraint))
+		}
+		def.Columns = append(def.Columns, colDef)
+	}
+
+	for _, constraint := range t.Constraints() {
+		conDef := TableConstraintDef{
+			Name: constraint.Name,
+		}
+		switch {
+		case constraint.PrimaryKey != nil:
+			conDef.PrimaryKey = &KeyDef{
+				Columns:    indexedColumnDefs(constraint.PrimaryKey.Columns()),
+				OnConflict: conflictOf(constraint.PrimaryKey.Conflict()),
+			}
+		case constraint.Unique != nil:
+			conDef.Unique = &KeyDef{
+				Columns:    indexedColumnDefs(constraint.Unique.Columns()),
+				OnConflict: conflictOf(constraint.Unique.Conflict()),
+			}
+		case constraint.Check != nil:
+			conDef.Check = constraint.Check.Inline()
+		case constraint.ForeignKey != nil:
+			columns := make([]string, len(constraint.ForeignKey.GetColumns()))
+			for i, col := range constraint.ForeignKey.GetColumns() {
+				columns[i] = utils.ToSnakeCase(col)
+			}
+			conDef.ForeignKey = &TableForeignKeyDef{
+				Columns:    columns,
+				References: referencesDef(constraint.ForeignKey),
+			}
+		}
+		def.Constraints = append(def.Constraints, conDef)
+	}
+	return def
+}
+
+func columnConstraintDef(constraint *column.Constraint) ColumnConstraintDef {
+	def := ColumnConstraintDef{
+		Name: constraint.Name,
+	}
+	switch constraint.Kind {
+	case column.PRIMARY_KEY:
+		def.PrimaryKey = &PrimaryKeyDef{
+			Order:         constraint.SortOrder.String(),
+			OnConflict:    conflictOf(constraint.Conflict),
+			Autoincrement: constraint.Autoincrement,
+		}
+	case column.NOT_NULL:
+		def.NotNull = &ConflictDef{OnConflict: conflictOf(constraint.Conflict)}
+	case column.UNIQUE:
+		def.Unique = &ConflictDef{OnConflict: conflictOf(constraint.Conflict)}
+	case column.CHECK:
+		def.Check = constraint.Expression
+	case column.DEFAULT:
+		def.Default = constraint.Expression
+	case column.COLLATE:
+		def.Collate = constraint.Collation
+	case column.REFERENCES:
+		ref := referencesDef(constraint.Reference)
+		def.References = &ref
+	case column.GENERATED:
+		def.Generated = &GeneratedDef{
+			Expression: constraint.Expression,
+			Always:     constraint.Always,
+			Storage:    constraint.Storage.String(),
+		}
+	}
+	return def
+}
+
+func referencesDef(ref *foreignkey.References) ReferencesDef {
+	return ReferencesDef{
+		Table:         ref.ForeignTableName(),
+		Columns:       ref.ForeignColumns(),
+		OnDelete:      ref.OnDeleteAction(),
+		OnUpdate:      ref.OnUpdateAction(),
+		Match:         ref.MatchName(),
+		Deferrable:    ref.DeferrableMode(),
+		NotDeferrable: ref.NotDeferrableMode(),
+	}
+}
+
+func indexDef(idx *index.Index) IndexDef {
+	def := IndexDef{
+		Name:        idx.Name(),
+		Table:       tableNameOf(idx.Table()),
+		Schema:      idx.SchemaName(),
+		Unique:      idx.IsUnique(),
+		IfNotExists: idx.IsIfNotExists(),
+		Columns:     indexedColumnDefs(idx.IndexedColumns()),
+	}
+	if where := idx.WhereExpression(); where != nil {
+		def.Where = where.Inline()
+	}
+	return def
+}
+
+func indexedColumnDefs(columns []*idxcol.IndexedColumn) []IndexedColumnDef {
+	defs := make([]IndexedColumnDef, len(columns))
+	for i, col := range columns {
+		defs[i] = IndexedColumnDef{
+			Column:  col.ColumnName(),
+			Collate: col.Collation(),
+			Order:   col.SortOrder().String(),
+		}
+		if col.ColumnName() == "" {
+			defs[i].Expression = col.Expression().Inline()
+		}
+	}
+	return defs
+}
+
+// conflictOf strips the ON CONFLICT keywords the builders store with the resolution
+func conflictOf(conflict string) string {
+	return strings.TrimPrefix(conflict, "ON CONFLICT ")
+}
+
+// tableNameOf returns the SQL name of a model, table or table name
+func tableNameOf(table any) string {
+	if name, ok := table.(string); ok {
+		return name
+	}
+	return reflectutil.GetTableName(table)
+}
+
+// tableRef is a table known only by its name, e.g. a table outside the schema file
+type tableRef string
+
+func (t tableRef) TableName() string {
+	return string(t)
+}
+
+// NewSchemaFromFile creates the schema described by a SchemaFile
+func NewSchemaFromFile(def *SchemaFile) (schema *Schema, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			schema, err = nil, fmt.Errorf("schema file: %v", r)
+		}
+	}()
+
+	l := &schemaLoader{
+		schema: NewSchema(def.Name),
+		tables: map[string]*table.Table{},
+	}
+	if err := l.load(def); err != nil {
+		return nil, err
+	}
+	return l.schema, nil
+}
+
+type schemaLoader struct {
+	schema *Schema
+	tables map[string]*table.Table
+}
+
+// table resolves a table name to the loaded table, names outside the file stay names
+func (l *schemaLoader) table(name string) any {
+	if t, ok := l.tables[name]; ok {
+		return t
+	}
+	return tableRef(name)
+}
+
+func (l *schemaLoader) load(def *SchemaFile) error {
+	for _, p := range def.Pragmas {
+		if p.Name == "" {
+			return fmt.Errorf("pragma without a name")
+		}
+		prag := pragmas.NewPragma(p.Schema, p.Name)
+		if p.Value != "" {
+			prag.ValueType(p.Value)
+		}
+		if p.Argument != "" {
+			prag.FuncType(p.Argument)
+		}
+		l.schema.pragmas = append(l.schema.pragmas, prag)
+	}
+
+	// The tables and their columns exist before any constraint is added,
+	// so foreign keys can point to tables and columns declared later
+	columns := make([][]*column.Column, len(def.Tables))
+	for i, tableDef := range def.Tables {
+		if tableDef.Name == "" {
+			return fmt.Errorf("table without a name")
+		}
+		if _, ok := l.tables[tableDef.Name]; ok {
+			return fmt.Errorf("table '%s' is declared twice", tableDef.Name)
+		}
+		t := table.NewDynamicTable(tableDef.Name)
+		for _, colDef := range tableDef.Columns {
+			if colDef.Name == "" {
+				return fmt.Errorf("table '%s' has a column without a name", tableDef.Name)
+			}
+			col := column.NewColumn(colDef.Name, types.SQLiteType(colDef.Type))
+			columns[i] = append(columns[i], col)
+			t.Column(col)
+		}
+		l.tables[tableDef.Name] = t
+		l.schema.tables = append(l.schema.tables, t)
+	}
+
+	for i, tableDef := range def.Tables {
+		if err := l.loadTable(l.tables[tableDef.Name], tableDef, columns[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, idxDef := range def.Indexes {
+		cols, err := l.indexedColumns(idxDef.Columns)
+		if err != nil {
+			return fmt.Errorf("index '%s': %w", idxDef.Name, err)
+		}
+		idx := index.NewIndex(l.table(idxDef.Table), idxDef.Name, cols)
+		if idxDef.Schema != "" {
+			idx.Schema(idxDef.Schema)
+		}
+		if idxDef.Unique {
+			idx.Unique()
+		}
+		if idxDef.IfNotExists {
+			idx.IfNotExists()
+		}
+		if idxDef.Where != "" {
+			idx.Where(expr.NewExpression(idxDef.Where))
+		}
+		l.schema.indexes = append(l.schema.indexes, idx)
+	}
+
+	for _, viewDef := range def.Views {
+		if viewDef.Select == "" {
+			return fmt.Errorf("view '%s' has no select", viewDef.Name)
+		}
+		v := view.NewView(viewDef.Name, selectstmt.NewRawSelect(viewDef.Select)).
+			Schema(viewDef.Schema).
+			Columns(viewDef.Columns...)
+		if viewDef.Temporary {
+			v.Temporary()
+		}
+		if viewDef.IfNotExists {
+			v.IfNotExists()
+		}
+		l.schema.views = append(l.schema.views, v)
+	}
+
+	for _, trigDef := range def.Triggers {
+		timing := trigger.Timing(strings.ToUpper(trigDef.Timing))
+		switch timing {
+		case trigger.NO_TIMING, trigger.BEFORE, trigger.AFTER, trigger.INSTEAD_OF:
+		default:
+			return fmt.Errorf("trigger '%s': unknown timing '%s'", trigDef.Name, trigDef.Timing)
+		}
+		event := trigger.Event(strings.ToUpper(trigDef.Event))
+		switch event {
+		case trigger.DELETE, trigger.INSERT, trigger.UPDATE:
+		default:
+			return fmt.Errorf("trigger '%s': unknown event '%s'", trigDef.Name, trigDef.Event)
+		}
+
+		t := trigger.NewTrigger(trigDef.Name, timing, event, l.table(trigDef.Table)).
+			Schema(trigDef.Schema).
+			Do(trigDef.Statements...)
+		if len(trigDef.Of) > 0 {
+			t.Of(trigDef.Of...)
+		}
+		if trigDef.Temporary {
+			t.Temporary()
+		}
+		if trigDef.IfNotExists {
+			t.IfNotExists()
+		}
+		if trigDef.ForEachRow {
+			t.ForEachRow()
+		}
+		if trigDef.When != "" {
+			t.When(expr.NewExpression(trigDef.When))
+		}
+		l.schema.triggers = append(l.schema.triggers, t)
+	}
+	return nil
+}
+
+func (l *schemaLoader) loadTable(t *table.Table, def TableDef, columns []*column.Column) error {
+	t.Schema(def.Schema)
+	if def.Temporary {
+		t.Temporary()
+	}
+	if def.IfNotExists {
+		t.IfNotExists()
+	}
+	if def.WithoutRowID {
+		t.WithouRowID()
+	}
+	if def.Strict {
+		t.Strict()
+	}
+	if def.As != "" {
+		if len(def.Columns) > 0 || len(def.Constraints) > 0 {
+			return fmt.Errorf("table '%s' can't have both columns and as", def.Name)
+		}
+		t.Select(selectstmt.NewRawSelect(def.As))
+		return nil
+	}
+
+	for i, colDef := range def.Columns {
+		for _, conDef := range colDef.Constraints {
+			if err := l.columnConstraint(columns[i], conDef); err != nil {
+				return fmt.Errorf("table '%s' column '%s': %w", def.Name, colDef.Name, err)
+			}
+		}
+	}
+
+	for _, conDef := range def.Constraints {
+		if err := l.tableConstraint(t, conDef); err != nil {
+			return fmt.Errorf("table '%s': %w", def.Name, err)
+		}
+	}
+	return nil
+}
+
+func (l *schemaLoader) columnConstraint(col *column.Column, def ColumnConstraintDef) error {
+	kinds := 0
+	for _, set := range []bool{def.PrimaryKey != nil, def.NotNull != nil, def.Unique != nil, def.Check != "",
+		def.Default != "", def.Collate != "", def.References != nil, def.Generated != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("a constraint has to set exactly one kind, it sets %d", kinds)
+	}
+
+	switch {
+	case def.PrimaryKey != nil:
+		order, err := sortorder.NewSortOrder(strings.ToUpper(def.PrimaryKey.Order))
+		if err != nil {
+			return err
+		}
+		col.PrimaryKey(def.Name, order, conflictClause(def.PrimaryKey.OnConflict), def.PrimaryKey.Autoincrement)
+	case def.NotNull != nil:
+		col.NotNull(def.Name, conflictClause(def.NotNull.OnConflict))
+	case def.Unique != nil:
+		col.Unique(def.Name, conflictClause(def.Unique.OnConflict))
+	case def.Check != "":
+		col.Check(def.Name, expr.NewExpression(def.Check))
+	case def.Default != "":
+		col.Default(def.Name, def.Default)
+	case def.Collate != "":
+		col.Collate(def.Name, def.Collate)
+	case def.References != nil:
+		ref := foreignkey.NewColumnReferences(col.Name(), l.table(def.References.Table), def.References.Columns)
+		l.referenceOptions(ref, def.References)
+		col.References(def.Name, ref)
+	case def.Generated != nil:
+		storage := generated.StorageType(strings.ToUpper(def.Generated.Storage))
+		switch storage {
+		case generated.NO_STORAGE, generated.STORED, generated.VIRTUAL:
+		default:
+			return fmt.Errorf("unknown storage '%s'", def.Generated.Storage)
+		}
+		col.Generated(def.Name, def.Generated.Always, expr.NewExpression(def.Generated.Expression), storage)
+	}
+	return nil
+}
+
+func (l *schemaLoader) tableConstraint(t *table.Table, def TableConstraintDef) error {
+	kinds := 0
+	for _, set := range []bool{def.PrimaryKey != nil, def.Unique != nil, def.Check != "", def.ForeignKey != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("a constraint has to set exactly one kind, it sets %d", kinds)
+	}
+
+	switch {
+	case def.PrimaryKey != nil:
+		cols, err := l.indexedColumns(def.PrimaryKey.Columns)
+		if err != nil {
+			return err
+		}
+		t.PrimaryKey(def.Name, primarykey.NewTablePrimaryKey(cols).OnConflict(conflictClause(def.PrimaryKey.OnConflict)))
+	case def.Unique != nil:
+		cols, err := l.indexedColumns(def.Unique.Columns)
+		if err != nil {
+			return err
+		}
+		t.Unique(def.Name, unique.NewTableUnique(cols).OnConflict(conflictClause(def.Unique.OnConflict)))
+	case def.Check != "":
+		t.Check(def.Name, expr.NewExpression(def.Check))
+	case def.ForeignKey != nil:
+		if len(def.ForeignKey.Columns) == 0 {
+			return fmt.Errorf("foreign key without columns")
+		}
+		ref := foreignkey.NewTableForeignTable(l.table(def.ForeignKey.References.Table), def.ForeignKey.Columns)
+		if len(def.ForeignKey.References.Columns) > 0 {
+			ref.ForeighColumns(def.ForeignKey.References.Columns)
+		}
+		l.referenceOptions(ref, &def.ForeignKey.References)
+		t.ForeignKey(def.Name, ref)
+	}
+	return nil
+}
+
+func (l *schemaLoader) referenceOptions(ref *foreignkey.References, def *ReferencesDef) {
+	ref.OnDelete(strings.ToUpper(def.OnDelete)).
+		OnUpdate(strings.ToUpper(def.OnUpdate)).
+		Match(def.Match)
+	if def.Deferrable != nil {
+		ref.Deferrable(strings.ToUpper(*def.Deferrable))
+	}
+	if def.NotDeferrable != nil {
+		ref.NotDeferrable(strings.ToUpper(*def.NotDeferrable))
+	}
+}
+
+func (l *schemaLoader) indexedColumns(defs []IndexedColumnDef) ([]*idxcol.IndexedColumn, error) {
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("no columns")
+	}
+	cols := make([]*idxcol.IndexedColumn, len(defs))
+	for i, def := range defs {
+		switch {
+		case def.Column != "" && def.Expression == "":
+			cols[i] = idxcol.NewIndexedColumnNames(def.Column)
+		case def.Column == "" && def.Expression != "":
+			cols[i] = idxcol.NewIndexedColumnExpresions(expr.NewExpression(def.Expression))
+		default:
+			return nil, fmt.Errorf("indexed column has to set exactly one of column and expression")
+		}
+		if def.Collate != "" {
+			cols[i].Collate(def.Collate)
+		}
+		switch strings.ToUpper(def.Order) {
+		case "":
+		case "ASC":
+			cols[i].ASC()
+		case "DESC":
+			cols[i].DESC()
+		default:
+			return nil, fmt.Errorf("invalid sort order: %s", def.Order)
+		}
+	}
+	return cols, nil
+}
+
+// conflictClause restores the ON CONFLICT keywords stripped by conflictOf
+func conflictClause(resolution string) string {
+	return ConflictClause(strings.ToUpper(resolution)).String()
+}
@@ -17,7 +17,7 @@ func (s SelectType) String() string {
 	return string(s)
 }
 
-// SELECT creates a new SELECT statement from a raw SQL string
+// SELECT creates a new SELECT statement, selectType is DISTINCT, ALL or NOTHING
 func SELECT(selectType SelectType, columns ...*ResultColumn) *Select {
 	covColumns := make([]*selectstmt.ResultColumn, len(columns))
 
@@ -30,6 +30,13 @@ func SELECT(selectType SelectType, columns ...*ResultColumn) *Select {
 	}
 }
 
+// RawSelect creates a SELECT statement from a raw SQL string, it's rendered as is
+func RawSelect(statement string) *Select {
+	return &Select{
+		Select: selectstmt.NewRawSelect(statement),
+	}
+}
+
 type Select struct {
 	*selectstmt.Select
 }
@@ -84,6 +91,31 @@ func (s *Select) OFFSET(offset int) *Select {
 	return s
 }
 
+// UNION appends the rows of the other SELECT without duplicates. ORDER BY, LIMIT and OFFSET
+// of the first SELECT apply to the whole compound, the other one can't have them.
+func (s *Select) UNION(other *Select) *Select {
+	s.Select.Union(other.Select)
+	return s
+}
+
+// UNION_ALL appends the rows of the other SELECT keeping duplicates
+func (s *Select) UNION_ALL(other *Select) *Select {
+	s.Select.UnionAll(other.Select)
+	return s
+}
+
+// INTERSECT keeps the rows also returned by the other SELECT
+func (s *Select) INTERSECT(other *Select) *Select {
+	s.Select.Intersect(other.Select)
+	return s
+}
+
+// EXCEPT removes the rows returned by the other SELECT
+func (s *Select) EXCEPT(other *Select) *Select {
+	s.Select.Except(other.Select)
+	return s
+}
+
 // NewExpressionColumn creates a new result column from an expression
 func NewExpressionColumn(expression *Expression) *ResultColumn {
 	return &ResultColumn{
@@ -198,12 +230,18 @@ func (j *Join) On(condition *Expression) *Join {
 	return j
 }
 
-// Using sets the USING columns for the JOIN
+// Using sets the USING columns for the JOIN, given by their Go field names
 func (j *Join) Using(columns ...string) *Join {
 	j.Join.Using(columns...)
 	return j
 }
 
+// UsingColumns sets the USING columns for the JOIN from column references, e.g. OrderCols.CustomerId
+func (j *Join) UsingColumns(columns ...ColumnRef) *Join {
+	j.Join.Using(columnFields(columns)...)
+	return j
+}
+
 // OrderDirection represents the direction of an ORDER BY clause
 type OrderDirection string
 
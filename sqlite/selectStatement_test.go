@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuildArgsOrder(t *testing.T) {
+	expensive := SELECT(NOTHING, NewExpressionColumn(NewExpression("category_id"))).
+		FROM(NewTableFrom("product")).
+		WHERE(GT(NewExpression("price"), Expr(100)))
+
+	stmt := SELECT(NOTHING, NewExpressionColumn(NewExpression("name"))).
+		FROM(NewTableFrom("category")).
+		WHERE(AND(
+			EQ(NewExpression("active"), Expr(true)),
+			IN_SELECT(NewExpression("id"), expensive),
+			LIKE(NewExpression("name"), Expr("O'%"), nil),
+		)).
+		UNION(SELECT(NOTHING, NewExpressionColumn(NewExpression("name"))).
+			FROM(NewTableFrom("archived_category")).
+			WHERE(EQ(NewExpression("year"), Expr(2024))))
+
+	query, args := stmt.Build()
+	wantQuery := "SELECT name FROM category" +
+		" WHERE active = ? AND id IN (SELECT category_id FROM product WHERE price > ?) AND name LIKE ?" +
+		" UNION SELECT name FROM archived_category WHERE year = ?"
+	if query != wantQuery {
+		t.Errorf("query = %q\nwant    %q", query, wantQuery)
+	}
+	if wantArgs := []any{true, 100, "O'%", 2024}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %#v, want %#v", args, wantArgs)
+	}
+
+	wantInline := "SELECT name FROM category" +
+		" WHERE active = TRUE AND id IN (SELECT category_id FROM product WHERE price > 100) AND name LIKE 'O''%'" +
+		" UNION SELECT name FROM archived_category WHERE year = 2024"
+	if inline := stmt.Inline(); inline != wantInline {
+		t.Errorf("Inline() = %q\nwant       %q", inline, wantInline)
+	}
+}
@@ -1,13 +1,36 @@
 package sqlite
 
 import (
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
 	table "github.com/Nevoral/sqlofi/internal/sqlite/Table"
 )
 
+// TableNamer is implemented by models choosing their own table name
+// instead of the snake_case name of the struct.
+type TableNamer interface {
+	TableName() string
+}
+
+// TableOptioner is implemented by models configuring their own table,
+// CREATE_TABLE applies the options to every table created from the model.
+type TableOptioner interface {
+	TableOptions(*Table)
+}
+
+// TableIndexer is implemented by models declaring their own indexes,
+// the Schema adds them together with the table.
+type TableIndexer interface {
+	Indexes() []*Index
+}
+
 func CREATE_TABLE(model any, foreignTablePtrs ...any) *Table {
-	return &Table{
+	t := &Table{
 		Table: table.NewTable(model, foreignTablePtrs),
 	}
+	if options, ok := reflectutil.Implements[TableOptioner](model); ok {
+		options.TableOptions(t)
+	}
+	return t
 }
 
 type Table struct {
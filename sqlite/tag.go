@@ -0,0 +1,12 @@
+package sqlite
+
+import (
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+)
+
+// ConvertTag rewrites a sqlofi tag written in the SQL-like syntax into the key=value syntax,
+// e.g. "PRIMARY KEY AUTOINCREMENT" becomes "pk,autoinc". Tags already using the key=value
+// syntax are returned unchanged.
+func ConvertTag(tag string) (string, error) {
+	return column.ConvertTag(tag)
+}
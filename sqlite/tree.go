@@ -0,0 +1,199 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// The tree helpers below query adjacency lists, tables whose rows refer to their parent
+// row with a column like `ParentId REFERENCES Category (Id)`. They return a SELECT of the
+// model columns followed by the depth column, 0 for the starting rows, and for PATHS and
+// TRAVERSE the path column. The SELECT can be filtered, ordered or joined further.
+
+// ANCESTORS selects the rows matching the filters and all their ancestors up to the root
+func ANCESTORS[C ColumnName](model any, parentColumn C, filters ...*Expression) *Select {
+	list := newAdjacency(model, parentColumn)
+	return list.recursive(treeQuery{
+		start: list.filter(filters, nil),
+		link:  EQ(list.column(list.table, list.key), list.column(list.cte, list.parent)),
+	})
+}
+
+// DESCENDANTS selects the rows matching the filters and all their descendants,
+// without filters it starts at the roots, the rows without a parent
+func DESCENDANTS[C ColumnName](model any, parentColumn C, filters ...*Expression) *Select {
+	list := newAdjacency(model, parentColumn)
+	return list.recursive(treeQuery{
+		start: list.filter(filters, ISNULL(list.column(list.table, list.parent))),
+		link:  EQ(list.column(list.table, list.parent), list.column(list.cte, list.key)),
+	})
+}
+
+// PATHS selects the descendants like DESCENDANTS with the path column, the labels of
+// the rows from the starting one joined by the separator, e.g. "Books/Fiction/Fantasy"
+func PATHS[C ColumnName](model any, parentColumn C, labelColumn C, separator string, filters ...*Expression) *Select {
+	list := newAdjacency(model, parentColumn)
+	label := list.column(list.table, list.field(columnField(labelColumn)))
+	return list.recursive(treeQuery{
+		start:    list.filter(filters, ISNULL(list.column(list.table, list.parent))),
+		link:     EQ(list.column(list.table, list.parent), list.column(list.cte, list.key)),
+		path:     CAST(label, TEXT),
+		nextPath: CONCAT(list.column(list.cte, "path"), Expr(separator), label),
+	})
+}
+
+// TRAVERSE selects the descendants like DESCENDANTS but it's safe for data with cycles,
+// every row is visited at most once per path. The path column holds the keys of the
+// rows from the starting one, e.g. "/1/4/9/".
+func TRAVERSE[C ColumnName](model any, parentColumn C, filters ...*Expression) *Select {
+	list := newAdjacency(model, parentColumn)
+	key := CAST(list.column(list.table, list.key), TEXT)
+	return list.recursive(treeQuery{
+		start:    list.filter(filters, ISNULL(list.column(list.table, list.parent))),
+		link:     EQ(list.column(list.table, list.parent), list.column(list.cte, list.key)),
+		path:     CONCAT(Expr("/"), key, Expr("/")),
+		nextPath: CONCAT(list.column(list.cte, "path"), key, Expr("/")),
+		guard:    EQ(FUNC("instr", list.column(list.cte, "path"), CONCAT(Expr("/"), key, Expr("/"))), Expr(0)),
+	})
+}
+
+// adjacency is a table whose parent column references its key column
+type adjacency struct {
+	model   any
+	table   string
+	cte     string
+	columns []string
+	key     string
+	parent  string
+}
+
+// newAdjacency reads the columns of the model, the key column is the one the parent
+// column references or the PRIMARY KEY when the reference names no column
+func newAdjacency[C ColumnName](model any, parentColumn C) *adjacency {
+	list := &adjacency{
+		model: model,
+		table: reflectutil.GetTableName(model),
+	}
+	list.cte = list.table + "_tree"
+	list.parent = list.field(columnField(parentColumn))
+
+	var ref *column.Column
+	for _, field := range reflectutil.GetStructFields(model) {
+		if tag, ok := field.Tag.Lookup("sqlofi"); !ok || tag == "-" {
+			continue
+		}
+		list.columns = append(list.columns, utils.ToSnakeCase(field.Name))
+
+		// Other columns may reference tables the helpers don't know, their errors are ignored
+		col, err := column.ParseStructField([]any{model}, field)
+		if err != nil {
+			if utils.ToSnakeCase(field.Name) == list.parent {
+				panic(err)
+			}
+			continue
+		}
+		if col.Name() == list.parent {
+			ref = col
+		}
+		if list.key == "" && col.IsPrimaryKey() {
+			list.key = col.Name()
+		}
+	}
+
+	if ref == nil {
+		panic(fmt.Errorf("Error column %s of %s has no sqlofi tag", list.parent, reflectutil.GetStructName(model)))
+	}
+	for _, constraint := range ref.Constraints() {
+		if constraint.Reference == nil {
+			continue
+		}
+		if constraint.Reference.ForeignTableName() != list.table {
+			panic(fmt.Errorf("Error column %s references %s, not its own table %s", list.parent, constraint.Reference.ForeignTableName(), list.table))
+		}
+		if columns := constraint.Reference.ForeignColumns(); len(columns) == 1 {
+			list.key = columns[0]
+		}
+	}
+	if list.key == "" {
+		panic(fmt.Errorf("Error column %s references no column and %s has no PRIMARY KEY", list.parent, list.table))
+	}
+	return list
+}
+
+// field returns the column name of the field, it panics when the model has no such field
+func (a *adjacency) field(field string) string {
+	if !slices.Contains(reflectutil.GetStructFieldsNames(a.model), field) {
+		panic(fmt.Errorf("Error field %s isn't present in %s", field, reflectutil.GetStructName(a.model)))
+	}
+	return utils.ToSnakeCase(field)
+}
+
+func (a *adjacency) column(table, name string) *Expression {
+	return NewExpression(table + "." + name)
+}
+
+// filter joins the filters with AND, byDefault is used without filters
+func (a *adjacency) filter(filters []*Expression, byDefault *Expression) *Expression {
+	switch len(filters) {
+	case 0:
+		return byDefault
+	case 1:
+		return filters[0]
+	}
+	return AND(filters...)
+}
+
+// treeQuery is the recursive part specific to a helper
+type treeQuery struct {
+	start    *Expression // WHERE of the starting rows, nil for all rows
+	link     *Expression // joins a row to the row found in the previous step
+	path     *Expression // path of the starting rows, nil without the path column
+	nextPath *Expression // path of the following rows
+	guard    *Expression // additional WHERE of the following rows
+}
+
+// recursive builds WITH RECURSIVE cte AS (start UNION ALL step) SELECT ... FROM cte
+func (a *adjacency) recursive(q treeQuery) *Select {
+	cteColumns := append(append([]string{}, a.columns...), "depth")
+	if q.path != nil {
+		cteColumns = append(cteColumns, "path")
+	}
+
+	startColumns := a.resultColumns(a.table)
+	stepColumns := a.resultColumns(a.table)
+	startColumns = append(startColumns, NewExpressionColumn(Expr(0)))
+	stepColumns = append(stepColumns, NewExpressionColumn(ADD(a.column(a.cte, "depth"), Expr(1))))
+	if q.path != nil {
+		startColumns = append(startColumns, NewExpressionColumn(q.path))
+		stepColumns = append(stepColumns, NewExpressionColumn(q.nextPath))
+	}
+
+	start := SELECT(NOTHING, startColumns...).FROM(NewTableFrom(a.table))
+	if q.start != nil {
+		start.WHERE(q.start)
+	}
+	step := SELECT(NOTHING, stepColumns...).
+		FROM(NewTableFrom(a.table).Join(NewTableJoin(INNER_JOIN, a.cte).On(q.link)))
+	if q.guard != nil {
+		step.WHERE(q.guard)
+	}
+
+	tree := CTE(a.cte, cteColumns...).AS(start.UNION_ALL(step))
+	result := make([]*ResultColumn, len(cteColumns))
+	for i, name := range cteColumns {
+		result[i] = NewExpressionColumn(NewExpression(name))
+	}
+	return WITH_RECURSIVE(tree).SELECT(NOTHING, result...).FROM(NewCTEFrom(tree))
+}
+
+func (a *adjacency) resultColumns(table string) []*ResultColumn {
+	columns := make([]*ResultColumn, len(a.columns))
+	for i, name := range a.columns {
+		columns[i] = NewExpressionColumn(a.column(table, name))
+	}
+	return columns
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	trigger "github.com/Nevoral/sqlofi/internal/sqlite/Trigger"
+)
+
+// TriggerTiming is the moment the trigger fires relative to its event
+type TriggerTiming = trigger.Timing
+
+const (
+	NO_TIMING  TriggerTiming = trigger.NO_TIMING
+	BEFORE     TriggerTiming = trigger.BEFORE
+	AFTER      TriggerTiming = trigger.AFTER
+	INSTEAD_OF TriggerTiming = trigger.INSTEAD_OF
+)
+
+// TriggerEvent is the statement that fires the trigger
+type TriggerEvent = trigger.Event
+
+const (
+	ON_DELETE TriggerEvent = trigger.DELETE
+	ON_INSERT TriggerEvent = trigger.INSERT
+	ON_UPDATE TriggerEvent = trigger.UPDATE
+)
+
+// CREATE_TRIGGER creates a trigger on the table, either a model, a *Table or a table name,
+// the body is added with Do.
+func CREATE_TRIGGER(name string, timing TriggerTiming, event TriggerEvent, table any) *Trigger {
+	return &Trigger{
+		Trigger: trigger.NewTrigger(name, timing, event, table),
+	}
+}
+
+type Trigger struct {
+	*trigger.Trigger
+}
+
+func (t *Trigger) Temporary() *Trigger {
+	t.Trigger.Temporary()
+	return t
+}
+
+func (t *Trigger) IfNotExists() *Trigger {
+	t.Trigger.IfNotExists()
+	return t
+}
+
+func (t *Trigger) Schema(schemaName string) *Trigger {
+	t.Trigger.Schema(schemaName)
+	return t
+}
+
+// Of limits an UPDATE trigger to updates of the given columns
+func (t *Trigger) Of(columns ...string) *Trigger {
+	t.Trigger.Of(columns...)
+	return t
+}
+
+func (t *Trigger) ForEachRow() *Trigger {
+	t.Trigger.ForEachRow()
+	return t
+}
+
+func (t *Trigger) When(condition *Expression) *Trigger {
+	t.Trigger.When(condition.Expression)
+	return t
+}
+
+// Do appends SQL statements to the trigger body, written without the closing semicolon
+func (t *Trigger) Do(statements ...string) *Trigger {
+	t.Trigger.Do(statements...)
+	return t
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TxMode is the locking behavior of BEGIN
+type TxMode string
+
+const (
+	BEGIN_DEFERRED  TxMode = "DEFERRED"  // locks the database on the first read or write
+	BEGIN_IMMEDIATE TxMode = "IMMEDIATE" // takes the write lock at once
+	BEGIN_EXCLUSIVE TxMode = "EXCLUSIVE" // also keeps readers out in rollback journal mode
+)
+
+func (m TxMode) String() string {
+	return string(m)
+}
+
+// TxOptions configures Schema.Tx, nil is a BEGIN_DEFERRED transaction
+type TxOptions struct {
+	Mode TxMode
+}
+
+// Tx runs fn in a transaction on a connection of the schema database. The transaction is
+// committed when fn returns nil and rolled back when it returns an error or panics,
+// the panic is raised again after the rollback.
+func (s *Schema) Tx(ctx context.Context, opts *TxOptions, fn func(tx *Tx) error) error {
+	if s.db == nil {
+		return fmt.Errorf("schema '%s' has no database connection, call OpenDBConnection first", s.name)
+	}
+	mode := BEGIN_DEFERRED
+	if opts != nil && opts.Mode != "" {
+		mode = opts.Mode
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN "+mode.String()); err != nil {
+		return err
+	}
+	tx := &Tx{conn: conn}
+	return tx.run(ctx, fn, "COMMIT", "ROLLBACK")
+}
+
+// Tx is a transaction on a single connection, it's an Executor for Query, Iter,
+// BulkInsert and NewRepo and runs built statements with Exec
+type Tx struct {
+	conn  *sql.Conn
+	depth int
+}
+
+// ExecContext runs a SQL statement in the transaction
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.conn.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a SQL query in the transaction
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.conn.QueryContext(ctx, query, args...)
+}
+
+// Exec runs a statement built by the package, e.g. an *Insert, *Update or *Delete
+func (t *Tx) Exec(ctx context.Context, stmt Statement) (sql.Result, error) {
+	query, args := stmt.Build()
+	return t.conn.ExecContext(ctx, query, args...)
+}
+
+// ExecDDL runs a schema statement built by the package, e.g. CREATE_TABLE or CREATE_INDEX
+func (t *Tx) ExecDDL(ctx context.Context, stmt interface{ Build() string }) (sql.Result, error) {
+	return t.conn.ExecContext(ctx, stmt.Build())
+}
+
+// Tx runs fn in a SAVEPOINT nested in the transaction. It's released when fn returns nil,
+// otherwise the changes made since the SAVEPOINT are rolled back and the outer
+// transaction continues.
+func (t *Tx) Tx(ctx context.Context, fn func(tx *Tx) error) error {
+	nested := &Tx{conn: t.conn, depth: t.depth + 1}
+	savepoint := fmt.Sprintf("sqlofi_%d", nested.depth)
+
+	if _, err := t.conn.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+	return nested.run(ctx, fn, "RELEASE "+savepoint, "ROLLBACK TO "+savepoint, "RELEASE "+savepoint)
+}
+
+// run calls fn and finishes the transaction or savepoint with the commit or rollback statements
+func (t *Tx) run(ctx context.Context, fn func(tx *Tx) error, commit string, rollback ...string) error {
+	// Finishing has to happen even when ctx was canceled meanwhile
+	finishCtx := context.WithoutCancel(ctx)
+
+	undo := func() error {
+		for _, statement := range rollback {
+			if _, err := t.conn.ExecContext(finishCtx, statement); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			undo()
+			panic(r)
+		}
+	}()
+
+	if err := fn(t); err != nil {
+		if rollbackErr := undo(); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	if _, err := t.conn.ExecContext(finishCtx, commit); err != nil {
+		undo()
+		return err
+	}
+	return nil
+}
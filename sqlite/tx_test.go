@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type txItem struct {
+	Id   int64  `sqlofi:"PRIMARY KEY AUTOINCREMENT"`
+	Name string `sqlofi:"NOT NULL"`
+}
+
+func openTestSchema(t *testing.T) *Schema {
+	t.Helper()
+	s := NewSchema("tx").Model(txItem{})
+	if err := s.OpenDBConnection("sqlite3", filepath.Join(t.TempDir(), "tx.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	s.SetUpDatabase()
+	return s
+}
+
+// itemNames returns the names of the committed items
+func itemNames(t *testing.T, s *Schema) []string {
+	t.Helper()
+	names, err := Query[string](context.Background(), s.db, rawQuery("SELECT name FROM tx_item ORDER BY id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return names
+}
+
+func insertItem(ctx context.Context, tx *Tx, name string) error {
+	return NewRepo[txItem](tx).Insert(ctx, &txItem{Name: name})
+}
+
+func TestTxCommitWithNestedRollback(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSchema(t)
+	errNested := errors.New("nested")
+
+	err := s.Tx(ctx, &TxOptions{Mode: BEGIN_IMMEDIATE}, func(tx *Tx) error {
+		if err := insertItem(ctx, tx, "outer"); err != nil {
+			return err
+		}
+		nested := tx.Tx(ctx, func(tx *Tx) error {
+			if err := insertItem(ctx, tx, "rolled back"); err != nil {
+				return err
+			}
+			return errNested
+		})
+		if !errors.Is(nested, errNested) {
+			t.Errorf("nested error = %v, want %v", nested, errNested)
+		}
+		return tx.Tx(ctx, func(tx *Tx) error {
+			return insertItem(ctx, tx, "released")
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := itemNames(t, s), []string{"outer", "released"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
+
+func TestTxRollbackOnError(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSchema(t)
+	errFailed := errors.New("failed")
+
+	err := s.Tx(ctx, nil, func(tx *Tx) error {
+		if err := insertItem(ctx, tx, "rolled back"); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Errorf("error = %v, want %v", err, errFailed)
+	}
+	if got := itemNames(t, s); len(got) != 0 {
+		t.Errorf("items = %v, want none", got)
+	}
+}
+
+func TestTxRollbackOnPanic(t *testing.T) {
+	ctx := context.Background()
+	s := openTestSchema(t)
+
+	recovered := func() (r any) {
+		defer func() { r = recover() }()
+		s.Tx(ctx, &TxOptions{Mode: BEGIN_EXCLUSIVE}, func(tx *Tx) error {
+			if err := insertItem(ctx, tx, "rolled back"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+		return nil
+	}()
+	if recovered != "boom" {
+		t.Errorf("recovered %v, want the panic raised again", recovered)
+	}
+	if got := itemNames(t, s); len(got) != 0 {
+		t.Errorf("items = %v, want none", got)
+	}
+
+	// The connection is usable after the rollback
+	if err := s.Tx(ctx, nil, func(tx *Tx) error { return insertItem(ctx, tx, "committed") }); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := itemNames(t, s), []string{"committed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+}
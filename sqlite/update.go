@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	reflectutil "github.com/Nevoral/sqlofi/internal/reflectUtil"
+	column "github.com/Nevoral/sqlofi/internal/sqlite/ColumnDef"
+	selectstmt "github.com/Nevoral/sqlofi/internal/sqlite/Select"
+	set "github.com/Nevoral/sqlofi/internal/sqlite/Set"
+	updatestmt "github.com/Nevoral/sqlofi/internal/sqlite/Update"
+	"github.com/Nevoral/sqlofi/internal/utils"
+)
+
+// UPDATE creates an UPDATE of the table of the model, or of the table named by a string
+func UPDATE(model any) *Update {
+	return &Update{
+		Update: updatestmt.NewUpdate(reflectutil.GetTableName(model)),
+		model:  model,
+	}
+}
+
+// UPDATE creates an UPDATE prefixed with the WITH clause
+func (w *With) UPDATE(model any) *Update {
+	u := UPDATE(model)
+	u.Update.With(w.With)
+	return u
+}
+
+// Update is an UPDATE statement, Build returns its SQL and the bound values
+type Update struct {
+	*updatestmt.Update
+	model any
+}
+
+// OR sets the conflict resolution, e.g. UPDATE OR REPLACE or UPDATE OR IGNORE
+func (u *Update) OR(action ConflictClause) *Update {
+	u.Update.Or(string(action))
+	return u
+}
+
+// AS sets an alias for the table
+func (u *Update) AS(alias string) *Update {
+	u.Update.Alias(alias)
+	return u
+}
+
+// SET appends assignments created by SET and SET_ROW
+func (u *Update) SET(assignments ...*Assignment) *Update {
+	u.Update.Set(setAssignments(assignments)...)
+	return u
+}
+
+// SetFromStruct assigns the values of the fields of v, a value of the model. Without fields
+// it assigns every column except the PRIMARY KEY, generated and AUTOINCREMENT columns.
+func (u *Update) SetFromStruct(v any, fields ...string) *Update {
+	value := modelValue(u.model, v, "SetFromStruct")
+	if len(fields) > 0 {
+		checkFields(u.model, fields)
+	} else {
+		columns, err := column.FieldColumns(u.model)
+		if err != nil {
+			panic(err)
+		}
+		for _, col := range columns {
+			if col.Writable() && !col.PrimaryKey {
+				fields = append(fields, col.Field)
+			}
+		}
+	}
+
+	assignments := make([]*set.Assignment, len(fields))
+	for i, field := range fields {
+		assignments[i] = set.NewAssignment([]string{utils.ToSnakeCase(field)}, bindField(value, field).Expression)
+	}
+	u.Update.Set(assignments...)
+	return u
+}
+
+// SetFromStructColumns assigns the values of v like SetFromStruct, the columns are given
+// by column references, e.g. UserCols.Email
+func (u *Update) SetFromStructColumns(v any, columns ...ColumnRef) *Update {
+	return u.SetFromStruct(v, columnFields(columns)...)
+}
+
+// FROM joins other tables, their columns can be used in SET and WHERE
+func (u *Update) FROM(from *From) *Update {
+	u.Update.From(from.From)
+	return u
+}
+
+// WHERE sets the condition of the updated rows
+func (u *Update) WHERE(condition *Expression) *Update {
+	u.Update.Where(condition.Expression)
+	return u
+}
+
+// RETURNING sets the columns returned for every updated row
+func (u *Update) RETURNING(columns ...*ResultColumn) *Update {
+	u.Update.Returning(resultColumns(columns)...)
+	return u
+}
+
+// ORDER_BY sets the order in which LIMIT picks the rows, it needs SQLite built
+// with SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+func (u *Update) ORDER_BY(orderBy ...*OrderBy) *Update {
+	orders := make([]*selectstmt.OrderBy, len(orderBy))
+	for i, order := range orderBy {
+		orders[i] = order.OrderBy
+	}
+	u.Update.OrderBy(orders...)
+	return u
+}
+
+// LIMIT limits the number of updated rows, it needs SQLite built with
+// SQLITE_ENABLE_UPDATE_DELETE_LIMIT
+func (u *Update) LIMIT(limit int) *Update {
+	u.Update.Limit(limit)
+	return u
+}
+
+// OFFSET skips rows before LIMIT
+func (u *Update) OFFSET(offset int) *Update {
+	u.Update.Offset(offset)
+	return u
+}
@@ -0,0 +1,21 @@
+package sqlite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateSetFromStructColumns(t *testing.T) {
+	user := insertUser{Id: 7, Email: "ada@example.com", Name: "Ada"}
+	query, args := UPDATE(insertUser{}).
+		SetFromStructColumns(user, ColOf[insertUser]("Name")).
+		WHERE(EQ(Expr(ColOf[insertUser]("Id")), Expr(user.Id))).
+		Build()
+
+	if want := "UPDATE insert_user SET name = ? WHERE insert_user.id = ?"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if want := []any{"Ada", int64(7)}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %#v, want %#v", args, want)
+	}
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	view "github.com/Nevoral/sqlofi/internal/sqlite/View"
+)
+
+// CREATE_VIEW creates a view named name showing the result of the statement
+func CREATE_VIEW(name string, statement *Select) *View {
+	return &View{
+		View: view.NewView(name, statement.Select),
+	}
+}
+
+type View struct {
+	*view.View
+}
+
+func (v *View) Temporary() *View {
+	v.View.Temporary()
+	return v
+}
+
+func (v *View) IfNotExists() *View {
+	v.View.IfNotExists()
+	return v
+}
+
+func (v *View) Schema(schemaName string) *View {
+	v.View.Schema(schemaName)
+	return v
+}
+
+// Columns names the columns of the view instead of the result columns of the SELECT
+func (v *View) Columns(columns ...string) *View {
+	v.View.Columns(columns...)
+	return v
+}
@@ -0,0 +1,204 @@
+package sqlite
+
+import (
+	"fmt"
+
+	expr "github.com/Nevoral/sqlofi/internal/sqlite/Expression"
+)
+
+// FrameExclude is the EXCLUDE clause of a window frame
+type FrameExclude string
+
+const (
+	NO_EXCLUDE          FrameExclude = ""
+	EXCLUDE_NO_OTHERS   FrameExclude = "NO OTHERS"
+	EXCLUDE_CURRENT_ROW FrameExclude = "CURRENT ROW"
+	EXCLUDE_GROUP       FrameExclude = "GROUP"
+	EXCLUDE_TIES        FrameExclude = "TIES"
+)
+
+func (f FrameExclude) String() string {
+	return string(f)
+}
+
+// WINDOW creates a window definition for OVER or the WINDOW clause of a SELECT.
+// baseWindow names a window of the WINDOW clause it extends, "" for none.
+func WINDOW(baseWindow string) *Window {
+	return &Window{
+		Window: &expr.Window{Base: baseWindow},
+	}
+}
+
+// Window is a window definition
+type Window struct {
+	*expr.Window
+}
+
+// PARTITION_BY sets the expressions partitioning the rows of the window
+func (w *Window) PARTITION_BY(expressions ...*Expression) *Window {
+	w.Window.PartitionBy = nodes(expressions)
+	return w
+}
+
+// ORDER_BY sets the order of the rows in a partition
+func (w *Window) ORDER_BY(orderBy ...*OrderBy) *Window {
+	w.Window.OrderBy = make([]expr.Ordering, len(orderBy))
+	for i, order := range orderBy {
+		w.Window.OrderBy[i] = expr.Ordering{Expression: order.Expression(), Direction: order.Direction()}
+	}
+	return w
+}
+
+// ROWS sets a frame counted in rows, end is nil for a frame without BETWEEN
+func (w *Window) ROWS(start, end *FrameBound, exclude FrameExclude) *Window {
+	return w.frame("ROWS", start, end, exclude)
+}
+
+// RANGE sets a frame of the rows whose ORDER BY value is within the offsets
+func (w *Window) RANGE(start, end *FrameBound, exclude FrameExclude) *Window {
+	return w.frame("RANGE", start, end, exclude)
+}
+
+// GROUPS sets a frame counted in groups of rows with the same ORDER BY value
+func (w *Window) GROUPS(start, end *FrameBound, exclude FrameExclude) *Window {
+	return w.frame("GROUPS", start, end, exclude)
+}
+
+func (w *Window) frame(unit string, start, end *FrameBound, exclude FrameExclude) *Window {
+	if start == nil {
+		panic(fmt.Errorf("Error %s frame without a start", unit))
+	}
+	frame := &expr.Frame{Unit: unit, Start: start.FrameBound, Exclude: exclude.String()}
+	if end != nil {
+		frame.End = end.FrameBound
+	}
+	w.Window.Frame = frame
+	return w
+}
+
+// FrameBound is a boundary of a window frame
+type FrameBound struct {
+	*expr.FrameBound
+}
+
+func UNBOUNDED_PRECEDING() *FrameBound {
+	return &FrameBound{FrameBound: &expr.FrameBound{Kind: "UNBOUNDED PRECEDING"}}
+}
+
+// PRECEDING is the row offset rows, groups or values before the current one
+func PRECEDING(offset *Expression) *FrameBound {
+	return &FrameBound{FrameBound: &expr.FrameBound{Kind: "PRECEDING", Offset: node(offset)}}
+}
+
+func CURRENT_ROW() *FrameBound {
+	return &FrameBound{FrameBound: &expr.FrameBound{Kind: "CURRENT ROW"}}
+}
+
+// FOLLOWING is the row offset rows, groups or values after the current one
+func FOLLOWING(offset *Expression) *FrameBound {
+	return &FrameBound{FrameBound: &expr.FrameBound{Kind: "FOLLOWING", Offset: node(offset)}}
+}
+
+func UNBOUNDED_FOLLOWING() *FrameBound {
+	return &FrameBound{FrameBound: &expr.FrameBound{Kind: "UNBOUNDED FOLLOWING"}}
+}
+
+// function returns a copy of the function call of the expression, it panics for other expressions
+func (e *Expression) function(clause string) *expr.Function {
+	fn, ok := e.Node().(*expr.Function)
+	if !ok {
+		panic(fmt.Errorf("Error %s of %s, it isn't a function call", clause, e.Inline()))
+	}
+	clone := *fn
+	return &clone
+}
+
+// OVER makes the function call a window function over the window definition
+func (e *Expression) OVER(window *Window) *Expression {
+	fn := e.function("OVER")
+	fn.Over = &expr.Over{Window: window.Window}
+	return newExpression(fn)
+}
+
+// OVER_WINDOW makes the function call a window function over a window of the WINDOW clause
+func (e *Expression) OVER_WINDOW(name string) *Expression {
+	fn := e.function("OVER")
+	fn.Over = &expr.Over{Name: name}
+	return newExpression(fn)
+}
+
+// FILTER limits the rows of an aggregate or window function to those matching the condition
+func (e *Expression) FILTER(condition *Expression) *Expression {
+	fn := e.function("FILTER")
+	fn.Filter = node(condition)
+	return newExpression(fn)
+}
+
+// WINDOW adds a named window to the WINDOW clause, OVER_WINDOW refers to it by the name
+func (s *Select) WINDOW(name string, window *Window) *Select {
+	s.Select.Window(name, window.Window)
+	return s
+}
+
+// ROW_NUMBER numbers the rows of the partition from 1
+func ROW_NUMBER() *Expression {
+	return FUNC("row_number")
+}
+
+// RANK is the rank of the row with gaps, rows with the same ORDER BY value share it
+func RANK() *Expression {
+	return FUNC("rank")
+}
+
+// DENSE_RANK is the rank of the row without gaps
+func DENSE_RANK() *Expression {
+	return FUNC("dense_rank")
+}
+
+// PERCENT_RANK is (rank - 1) / (rows in the partition - 1)
+func PERCENT_RANK() *Expression {
+	return FUNC("percent_rank")
+}
+
+// CUME_DIST is the cumulative distribution, row number / rows in the partition
+func CUME_DIST() *Expression {
+	return FUNC("cume_dist")
+}
+
+// NTILE splits the partition into groups and returns the group of the row
+func NTILE(groups *Expression) *Expression {
+	return FUNC("ntile", groups)
+}
+
+// LAG returns the expression of the row offset rows before, offset and the
+// default used when there is no such row are optional
+func LAG(expression *Expression, offsetAndDefault ...*Expression) *Expression {
+	if len(offsetAndDefault) > 2 {
+		panic("LAG takes at most an offset and a default")
+	}
+	return FUNC("lag", append([]*Expression{expression}, offsetAndDefault...)...)
+}
+
+// LEAD returns the expression of the row offset rows after, offset and the
+// default used when there is no such row are optional
+func LEAD(expression *Expression, offsetAndDefault ...*Expression) *Expression {
+	if len(offsetAndDefault) > 2 {
+		panic("LEAD takes at most an offset and a default")
+	}
+	return FUNC("lead", append([]*Expression{expression}, offsetAndDefault...)...)
+}
+
+// FIRST_VALUE returns the expression of the first row of the frame
+func FIRST_VALUE(expression *Expression) *Expression {
+	return FUNC("first_value", expression)
+}
+
+// LAST_VALUE returns the expression of the last row of the frame
+func LAST_VALUE(expression *Expression) *Expression {
+	return FUNC("last_value", expression)
+}
+
+// NTH_VALUE returns the expression of the n-th row of the frame
+func NTH_VALUE(expression *Expression, n *Expression) *Expression {
+	return FUNC("nth_value", expression, n)
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	with "github.com/Nevoral/sqlofi/internal/sqlite/With"
+)
+
+// WITH creates the WITH prefix of a statement with common table expressions
+func WITH(tableExpressions ...*CommonTableExpression) *With {
+	return newWith(false, tableExpressions)
+}
+
+// WITH_RECURSIVE creates the WITH RECURSIVE prefix, its common table expressions
+// can refer to themselves
+func WITH_RECURSIVE(tableExpressions ...*CommonTableExpression) *With {
+	return newWith(true, tableExpressions)
+}
+
+func newWith(recursive bool, tableExpressions []*CommonTableExpression) *With {
+	covTables := make([]*with.TableExpresions, len(tableExpressions))
+	for i, table := range tableExpressions {
+		covTables[i] = table.TableExpresions
+	}
+	return &With{
+		With: with.NewWithClause(recursive, covTables...),
+	}
+}
+
+// With is the WITH prefix of a SELECT, INSERT, UPDATE or DELETE statement
+type With struct {
+	*with.With
+}
+
+// SELECT creates a SELECT statement prefixed with the WITH clause
+func (w *With) SELECT(selectType SelectType, columns ...*ResultColumn) *Select {
+	s := SELECT(selectType, columns...)
+	s.Select.With(w.With)
+	return s
+}
+
+// CTE creates a common table expression, name is its name or a model whose table
+// name it takes. Columns optionally name the result columns of its SELECT.
+func CTE(name any, columns ...string) *CommonTableExpression {
+	return &CommonTableExpression{
+		TableExpresions: with.NewTableExpresion(name, columns...),
+	}
+}
+
+// CommonTableExpression is a named SELECT of the WITH clause
+type CommonTableExpression struct {
+	*with.TableExpresions
+}
+
+// AS sets the SELECT of the common table expression, for WITH_RECURSIVE it's
+// usually the initial SELECT with a UNION or UNION_ALL of the recursive one
+func (c *CommonTableExpression) AS(selectStmt *Select) *CommonTableExpression {
+	c.TableExpresions.WithSelect(selectStmt.Select)
+	return c
+}
+
+// MATERIALIZED makes SQLite compute the common table expression once
+func (c *CommonTableExpression) MATERIALIZED() *CommonTableExpression {
+	c.TableExpresions.Materialized()
+	return c
+}
+
+// NOT_MATERIALIZED lets SQLite inline the common table expression into the query
+func (c *CommonTableExpression) NOT_MATERIALIZED() *CommonTableExpression {
+	c.TableExpresions.NotMaterialized()
+	return c
+}
+
+// NewCTEFrom creates a new FROM clause with a common table expression
+func NewCTEFrom(cte *CommonTableExpression) *From {
+	return NewTableFrom(cte.Name())
+}
+
+// NewCTEJoin creates a new JOIN with a common table expression
+func NewCTEJoin(joinType JoinType, cte *CommonTableExpression) *Join {
+	return NewTableJoin(joinType, cte.Name())
+}